@@ -0,0 +1,99 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// repairMoves is how many swaps per item repairSort will spend on
+// insertion sort before deciding the data wasn't nearly sorted after all.
+const repairMoves = 4
+
+// repairSort finishes sorting nearly-sorted data: it runs an insertion
+// sort, which is fast when items are only slightly out of place, and bails
+// to a full quicksort if that takes more than repairMoves swaps per item.
+func repairSort(data sort.Interface) {
+	if !insertionRepair(data, repairMoves*data.Len()) {
+		Quicksort(data)
+	}
+}
+
+// insertionRepair insertion sorts data, giving up and returning false
+// once it's made budget swaps.
+func insertionRepair(data sort.Interface, budget int) bool {
+	l := data.Len()
+	for i := 1; i < l; i++ {
+		for j := i; j > 0 && data.Less(j, j-1); j-- {
+			if budget == 0 {
+				return false
+			}
+			data.Swap(j, j-1)
+			budget--
+		}
+	}
+	return true
+}
+
+// ByApproxUint64 sorts data by Less, using Key only as a hint.  It radix
+// sorts by Key, then repairs any items still out of order according to
+// Less, so unlike ByUint64 it never panics when Key and Less disagree.
+//
+// The contract for Key is loosened to "approximately monotonic": when Key(i)
+// is less than Key(j), item i should usually not sort after item j.  A lossy
+// or noisy key (say, a coarse hash of a float, or a timestamp with clock
+// skew) qualifies.  The result is correctly ordered by Less whatever Key
+// returns, but the further Key strays from Less's order, the more the
+// repair pass costs, up to the price of a plain Quicksort.
+func ByApproxUint64(data Uint64Interface) {
+	l := data.Len()
+	if l < qSortCutoff {
+		qSort(data, 0, l)
+		return
+	}
+
+	shift := guessIntShift(data, 0, l)
+	parallelSort(data, radixSortUint64, task{offs: int(shift), end: l})
+	repairSort(data)
+}
+
+// topBits makes a Uint64Interface's items look equal unless they differ in
+// the bits kept by mask.
+type topBits struct {
+	Uint64Interface
+	mask uint64
+}
+
+func (d topBits) Key(i int) uint64 { return d.Uint64Interface.Key(i) & d.mask }
+func (d topBits) Less(i, j int) bool {
+	return d.Uint64Interface.Key(i)&d.mask < d.Uint64Interface.Key(j)&d.mask
+}
+
+// ByUint64ApproxBits orders data only by the top bits of its keys:
+// afterwards, items are sorted by Key(i)>>(64-bits), but items that agree
+// in those bits are left in no particular order.  It's for coarse binning
+// (histograms, bucketed display) where the order inside a bin doesn't
+// matter, and skips the radix passes and comparisons that would sort
+// within bins, which saves the most when bins hold many items each.
+// data.Less is never called, and because order within a bin
+// isn't promised, there's no check of the result afterwards.  bits <= 0
+// leaves data alone; bits >= 64 orders by the full key (still without
+// calling Less to break ties).
+func ByUint64ApproxBits(data Uint64Interface, bits int) {
+	if bits <= 0 {
+		return
+	}
+	mask := ^uint64(0)
+	if bits < 64 {
+		mask <<= uint(64 - bits)
+	}
+	coarse := topBits{data, mask}
+	l := coarse.Len()
+	if l < qSortCutoff {
+		qSort(coarse, 0, l)
+		return
+	}
+	shift := guessIntShift(coarse, 0, l)
+	parallelSort(coarse, radixSortUint64, task{offs: int(shift), end: l})
+}
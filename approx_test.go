@@ -0,0 +1,101 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// noisyKeyInts sorts ints by value, with a Key that's off by up to noise.
+type noisyKeyInts struct {
+	IntSlice
+	noise []int
+}
+
+func (p noisyKeyInts) Swap(i, j int) {
+	p.IntSlice.Swap(i, j)
+	p.noise[i], p.noise[j] = p.noise[j], p.noise[i]
+}
+func (p noisyKeyInts) Key(i int) uint64 { return uint64(p.IntSlice[i] + p.noise[i]) }
+
+func TestByApproxUint64(t *testing.T) {
+	for _, noise := range []int{0, 3, 1000, 1 << 30} {
+		n := 10000
+		data := noisyKeyInts{make(IntSlice, n), make([]int, n)}
+		for i := 0; i < n; i++ {
+			data.IntSlice[i] = rand.Intn(1 << 20)
+			data.noise[i] = rand.Intn(noise + 1)
+		}
+		varyQSortCutoff(func() {
+			ByApproxUint64(data)
+			if !sort.IsSorted(data) {
+				t.Errorf("noise=%d: didn't sort", noise)
+			}
+			rand.Shuffle(n, data.Swap)
+		})
+	}
+}
+
+func TestByUint64ApproxBits(t *testing.T) {
+	for _, bits := range []int{0, 1, 8, 13, 64, 100} {
+		data := make([]uint64, 10000)
+		for i := range data {
+			data[i] = uint64(rand.Int63()) << 1
+		}
+		orig := append([]uint64(nil), data...)
+		shift := uint(0)
+		if bits < 64 {
+			shift = uint(64 - bits)
+		}
+		varyQSortCutoff(func() {
+			ByUint64ApproxBits(Uint64Slice(data), bits)
+			if bits <= 0 {
+				for i := range data {
+					if data[i] != orig[i] {
+						t.Fatalf("bits=%d moved data", bits)
+					}
+				}
+				return
+			}
+			for i := 1; i < len(data); i++ {
+				if data[i]>>shift < data[i-1]>>shift {
+					t.Fatalf("bits=%d: out of order at %d", bits, i)
+				}
+			}
+			rand.Shuffle(len(data), Uint64Slice(data).Swap)
+		})
+	}
+}
+
+func benchApprox(b *testing.B, sorter func([]uint64)) {
+	b.StopTimer()
+	data := make([]uint64, 1<<16)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = uint64(rand.Int63())
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkByUint64Full(b *testing.B) {
+	benchApprox(b, func(a []uint64) { ByUint64(Uint64Slice(a)) })
+}
+
+func BenchmarkByUint64ApproxBits8(b *testing.B) {
+	benchApprox(b, func(a []uint64) { ByUint64ApproxBits(Uint64Slice(a), 8) })
+}
+
+func BenchmarkByUint64ApproxBits16(b *testing.B) {
+	benchApprox(b, func(a []uint64) { ByUint64ApproxBits(Uint64Slice(a), 16) })
+}
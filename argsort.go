@@ -0,0 +1,117 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// Argsorts: finding the permutation that sorts data, and leaving the data
+// where it is.
+
+// An Argsorter finds the permutations that would sort collections
+// ("argsorts") without reordering the collections themselves.  It keeps
+// one index buffer and reuses it on every call, so argsorting many
+// same-sized collections (say, each column of a table) doesn't allocate
+// after the first call.  The zero value is ready to use.  An Argsorter is
+// not safe for concurrent use.
+type Argsorter struct {
+	idx []int
+}
+
+// indices returns the reused buffer holding 0, 1, ..., n-1.
+func (s *Argsorter) indices(n int) []int {
+	if cap(s.idx) < n {
+		s.idx = make([]int, n)
+	}
+	s.idx = s.idx[:n]
+	for i := range s.idx {
+		s.idx[i] = i
+	}
+	return s.idx
+}
+
+// argUint64 sorts a permutation of data by data's keys.
+type argUint64 struct {
+	data Uint64Interface
+	idx  []int
+}
+
+func (p argUint64) Len() int           { return len(p.idx) }
+func (p argUint64) Less(i, j int) bool { return p.data.Less(p.idx[i], p.idx[j]) }
+func (p argUint64) Swap(i, j int)      { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p argUint64) Key(i int) uint64   { return p.data.Key(p.idx[i]) }
+
+type argInt64 struct {
+	data Int64Interface
+	idx  []int
+}
+
+func (p argInt64) Len() int           { return len(p.idx) }
+func (p argInt64) Less(i, j int) bool { return p.data.Less(p.idx[i], p.idx[j]) }
+func (p argInt64) Swap(i, j int)      { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p argInt64) Key(i int) int64    { return p.data.Key(p.idx[i]) }
+
+type argString struct {
+	data StringInterface
+	idx  []int
+}
+
+func (p argString) Len() int           { return len(p.idx) }
+func (p argString) Less(i, j int) bool { return p.data.Less(p.idx[i], p.idx[j]) }
+func (p argString) Swap(i, j int)      { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p argString) Key(i int) string   { return p.data.Key(p.idx[i]) }
+
+type argBytes struct {
+	data BytesInterface
+	idx  []int
+}
+
+func (p argBytes) Len() int           { return len(p.idx) }
+func (p argBytes) Less(i, j int) bool { return p.data.Less(p.idx[i], p.idx[j]) }
+func (p argBytes) Swap(i, j int)      { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p argBytes) Key(i int) []byte   { return p.data.Key(p.idx[i]) }
+
+// ByUint64 returns the permutation p that sorts data by its uint64 key:
+// data's smallest item is at index p[0], the next at p[1], and so on.  It
+// only calls data's Len, Less and Key methods, never Swap.  The returned
+// slice is s's buffer, so it's only valid until s is used again.
+func (s *Argsorter) ByUint64(data Uint64Interface) []int {
+	idx := s.indices(data.Len())
+	ByUint64(argUint64{data, idx})
+	return idx
+}
+
+// ByInt64 is like ByUint64 for data sorted by an int64 key.
+func (s *Argsorter) ByInt64(data Int64Interface) []int {
+	idx := s.indices(data.Len())
+	ByInt64(argInt64{data, idx})
+	return idx
+}
+
+// ByString is like ByUint64 for data sorted by a string key.
+func (s *Argsorter) ByString(data StringInterface) []int {
+	idx := s.indices(data.Len())
+	ByString(argString{data, idx})
+	return idx
+}
+
+// ByBytes is like ByUint64 for data sorted by a []byte key.
+func (s *Argsorter) ByBytes(data BytesInterface) []int {
+	idx := s.indices(data.Len())
+	ByBytes(argBytes{data, idx})
+	return idx
+}
+
+// ArgsortUint64 returns a newly allocated permutation that sorts data by
+// its uint64 key, leaving data unchanged.  Use an Argsorter to reuse the
+// permutation's memory across calls.
+func ArgsortUint64(data Uint64Interface) []int { return new(Argsorter).ByUint64(data) }
+
+// ArgsortInt64 is like ArgsortUint64 for data sorted by an int64 key.
+func ArgsortInt64(data Int64Interface) []int { return new(Argsorter).ByInt64(data) }
+
+// ArgsortString is like ArgsortUint64 for data sorted by a string key.
+func ArgsortString(data StringInterface) []int { return new(Argsorter).ByString(data) }
+
+// ArgsortBytes is like ArgsortUint64 for data sorted by a []byte key.
+func ArgsortBytes(data BytesInterface) []int { return new(Argsorter).ByBytes(data) }
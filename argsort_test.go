@@ -0,0 +1,75 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestArgsort(t *testing.T) {
+	var s Argsorter
+	for _, n := range []int{0, 1, 100, 10000} {
+		ints := make([]int, n)
+		strs := make([]string, n)
+		for i := range ints {
+			ints[i] = rand.Intn(1000) - 500
+			strs[i] = strconv.Itoa(ints[i])
+		}
+		orig := append([]int(nil), ints...)
+		varyQSortCutoff(func() {
+			p := s.ByInt64(IntSlice(ints))
+			for i := 1; i < len(p); i++ {
+				if ints[p[i]] < ints[p[i-1]] {
+					t.Fatalf("n=%d: int argsort out of order at %d", n, i)
+				}
+			}
+			p = ArgsortString(StringSlice(strs))
+			for i := 1; i < len(p); i++ {
+				if strs[p[i]] < strs[p[i-1]] {
+					t.Fatalf("n=%d: string argsort out of order at %d", n, i)
+				}
+			}
+		})
+		for i := range ints {
+			if ints[i] != orig[i] {
+				t.Fatalf("n=%d: argsort moved data", n)
+			}
+		}
+	}
+}
+
+func benchArgsort(b *testing.B, argsort func(col Uint64Slice) []int) {
+	b.StopTimer()
+	cols := make([]Uint64Slice, 16)
+	for i := range cols {
+		cols[i] = make(Uint64Slice, 1<<12)
+		for j := range cols[i] {
+			cols[i][j] = uint64(rand.Int63())
+		}
+	}
+	b.StartTimer()
+	for i := 0; i < b.N; i++ {
+		for _, col := range cols {
+			argsort(col)
+		}
+	}
+}
+
+func BenchmarkArgsortFresh(b *testing.B) {
+	b.ReportAllocs()
+	benchArgsort(b, func(col Uint64Slice) []int { return ArgsortUint64(col) })
+}
+
+func BenchmarkArgsortReused(b *testing.B) {
+	b.ReportAllocs()
+	var s Argsorter
+	benchArgsort(b, func(col Uint64Slice) []int { return s.ByUint64(col) })
+}
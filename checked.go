@@ -0,0 +1,59 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "fmt"
+
+// A SortError reports that the check run after a radix sort found data
+// out of order.  The data is left in whatever order the sort reached; no
+// swaps are undone.
+type SortError struct {
+	// Index is where the check failed: data.Less(Index, Index-1) was true.
+	Index int
+	// KeyMismatch is true if Key orders items Index-1 and Index the
+	// opposite way from Less, meaning Key and Less are inconsistent (for
+	// float keys, sortutil's Key functions may help).  If it's false, the
+	// keys were in order but Less still wasn't satisfied, which points to
+	// a data race, a bug in this package, or a subtler bug in data's
+	// methods.
+	KeyMismatch bool
+
+	msg string
+}
+
+func (e *SortError) Error() string {
+	return fmt.Sprintf("%s (at index %d)", e.msg, e.Index)
+}
+
+// mustSort panics if err, from one of the Checked sorts' helpers, is
+// non-nil.  The panic value is the plain message string, as the sorts
+// have always panicked with.
+func mustSort(err error) {
+	if err != nil {
+		panic(err.(*SortError).msg)
+	}
+}
+
+// ByUint64Checked is ByUint64, but returns a *SortError instead of
+// panicking if the sorted data fails the check afterwards.
+func ByUint64Checked(data Uint64Interface) error {
+	return byUint64(data, parallelSort, true, nil)
+}
+
+// ByInt64Checked is ByInt64, returning errors like ByUint64Checked.
+func ByInt64Checked(data Int64Interface) error {
+	return byInt64(data, parallelSort, true, nil)
+}
+
+// ByStringChecked is ByString, returning errors like ByUint64Checked.
+func ByStringChecked(data StringInterface) error {
+	return byString(data, parallelSort, true, nil)
+}
+
+// ByBytesChecked is ByBytes, returning errors like ByUint64Checked.
+func ByBytesChecked(data BytesInterface) error {
+	return byBytes(data, parallelSort, true, nil)
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// ByUint64Chunked sorts data by a uint64 key like ByUint64, but with a
+// different parallel strategy: it cuts data into one contiguous chunk per
+// worker, radix sorts each chunk on its own goroutine, then merges the
+// chunks.  Each chunk's swaps stay within the chunk, where ByUint64's
+// first pass swaps items across the whole collection.  The merge reads
+// only keys: it's split into one part per worker at keys sampled from
+// the chunks, each part merged on its own goroutine into a list of where
+// each item goes, and then data's items are moved into place with about
+// one Swap each, following the cycles of that list, serially.
+//
+// It uses O(n) extra memory, two words (16 bytes on 64-bit platforms) per
+// item, for the keys and the final order, and it does more work in all
+// than ByUint64: with 4 workers on one core, BenchmarkByUint64Chunked in
+// chunked_test.go took about 2.5 times as long as ByUint64 on 1M random
+// keys.  So it can only win with idle cores and a Swap costly enough that
+// keeping swaps within chunks pays for the merge; measure before using
+// it.  With one worker (see MaxProcs), it just calls ByUint64.  Items
+// with equal keys end up in no particular order.
+func ByUint64Chunked(data Uint64Interface) {
+	l := data.Len()
+	w := workers(l)
+	if w == 1 {
+		ByUint64(data)
+		return
+	}
+
+	// sort each chunk serially, all at once
+	serial := &settings{procs: 1}
+	keys := make([]uint64, l)
+	inStripes(0, l, w, func(s, lo, hi int) {
+		var step func(task)
+		step = func(t task) { radixSortUint64(data, t, step) }
+		step(task{offs: int(guessIntShift(data, lo, hi)), pos: lo, end: hi, cfg: serial})
+		for i := lo; i < hi; i++ {
+			keys[i] = data.Key(i)
+		}
+	})
+
+	permute(data, mergeChunks(keys, w))
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if data.Less(i, i-1) {
+			if data.Key(i) > data.Key(i-1) {
+				panic(keyPanicMessage + keyUint64Help)
+			}
+			panic(panicMessage)
+		}
+	}
+}
+
+// mergeChunks returns the order that merges keys' w sorted chunks, as
+// inStripes cuts them: order[i] is the index in keys of the ith smallest.
+// The output is split into w parts, each merged on its own goroutine.
+func mergeChunks(keys []uint64, w int) []int {
+	l := len(keys)
+	chunks := make([][]uint64, w)
+	samples := make([]uint64, 0, w*w)
+	for c := range chunks {
+		chunks[c] = keys[l*c/w : l*(c+1)/w]
+		for s := 0; s < w; s++ {
+			if len(chunks[c]) > 0 {
+				samples = append(samples, chunks[c][len(chunks[c])*s/w])
+			}
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	// part p is every key from splitters[p-1] up to splitters[p]; cuts[p]
+	// is where in each chunk it starts
+	cuts := make([][]int, w+1)
+	for p := range cuts {
+		cuts[p] = make([]int, w)
+		for c, chunk := range chunks {
+			switch p {
+			case 0:
+			case w:
+				cuts[p][c] = len(chunk)
+			default:
+				v := samples[len(samples)*p/w]
+				cuts[p][c] = sort.Search(len(chunk), func(i int) bool { return chunk[i] >= v })
+			}
+		}
+	}
+
+	order := make([]int, l)
+	starts := make([]int, w+1)
+	for p := 0; p < w; p++ {
+		starts[p+1] = starts[p]
+		for c := range chunks {
+			starts[p+1] += cuts[p+1][c] - cuts[p][c]
+		}
+	}
+	inStripes(0, w, w, func(p, _, _ int) {
+		out, next := order[starts[p]:starts[p+1]], append([]int(nil), cuts[p]...)
+		for o := range out {
+			// take the smallest key at the head of a chunk
+			best := -1
+			for c, i := range next {
+				if i < cuts[p+1][c] && (best < 0 || chunks[c][i] < chunks[best][next[best]]) {
+					best = c
+				}
+			}
+			out[o] = l*best/w + next[best]
+			next[best]++
+		}
+	})
+	return order
+}
@@ -0,0 +1,51 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByUint64Chunked(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 3, 4} {
+		runtime.GOMAXPROCS(procs)
+		for _, n := range []int{0, 100, 10000, 100001} {
+			for _, distinct := range []int{1, 10, 1 << 30} {
+				data := make(Uint64Slice, n)
+				for i := range data {
+					data[i] = uint64(rand.Intn(distinct))
+				}
+				ByUint64Chunked(data)
+				if !Uint64sAreSorted(data) {
+					t.Errorf("procs=%d, n=%d, %d distinct: didn't sort", procs, n, distinct)
+				}
+			}
+		}
+	}
+}
+
+func benchChunked(b *testing.B, sorter func(Uint64Interface)) {
+	b.StopTimer()
+	data := make(Uint64Slice, 1<<20)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = rand.Uint64()
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+// Compare these with -cpu 4 (or more) on a multicore machine.
+func BenchmarkByUint64Chunked(b *testing.B)   { benchChunked(b, ByUint64Chunked) }
+func BenchmarkByUint64Unchunked(b *testing.B) { benchChunked(b, ByUint64) }
@@ -0,0 +1,64 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"bytes"
+	"sort"
+)
+
+// cachedBytes sorts data by precomputed []byte keys, such as collation
+// keys or normalized copies of data's own, breaking ties with data's
+// Less and swapping data's items along with the keys.
+type cachedBytes struct {
+	data sort.Interface
+	keys [][]byte
+}
+
+func (p cachedBytes) Len() int { return len(p.keys) }
+func (p cachedBytes) Less(i, j int) bool {
+	if c := bytes.Compare(p.keys[i], p.keys[j]); c != 0 {
+		return c < 0
+	}
+	return p.data.Less(i, j)
+}
+func (p cachedBytes) Swap(i, j int) {
+	p.data.Swap(i, j)
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+func (p cachedBytes) Key(i int) []byte { return p.keys[i] }
+
+// ByStringCollated sorts data by the collation keys sortKey makes from
+// its string keys, for orderings like a locale's that no byte-by-byte
+// comparison of the strings can give.  It's meant for the keys of
+// golang.org/x/text/collate, which compare bytewise in the language's
+// order:
+//
+//	c := collate.New(language.German)
+//	var buf collate.Buffer
+//	sorts.ByStringCollated(data, func(s string) []byte { return c.KeyFromString(&buf, s) })
+//
+// As in ByStringNormalized, sortKey is called once per item, up front;
+// the keys are copied into one buffer, so sortKey may reuse its own
+// between calls, and radix sorted as ByBytes sorts.  Items with equal
+// keys, which collators give strings they consider the same, are ordered
+// by data.Less.
+func ByStringCollated(data StringInterface, sortKey func(string) []byte) {
+	l := data.Len()
+	ends := make([]int, l)
+	var buf []byte
+	for i := range ends {
+		buf = append(buf, sortKey(data.Key(i))...)
+		ends[i] = len(buf)
+	}
+	// slice the keys out of buf once it's done growing
+	keys, start := make([][]byte, l), 0
+	for i, end := range ends {
+		keys[i] = buf[start:end:end]
+		start = end
+	}
+	ByBytes(cachedBytes{data, keys})
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// toyCollation is a stand-in for a collator: its keys fold case and
+// strip accents, so "été" sorts among the e's, not after "z".  They reuse
+// one buffer, as collate.Buffer's do.
+type toyCollation struct{ buf []byte }
+
+var unaccent = strings.NewReplacer("é", "e", "è", "e", "É", "e")
+
+func (c *toyCollation) key(s string) []byte {
+	c.buf = append(c.buf[:0], unaccent.Replace(strings.ToLower(s))...)
+	return c.buf
+}
+
+func TestByStringCollated(t *testing.T) {
+	words := []string{"zèbre", "été", "Eté", "ete", "alpha", "étage", "Zebra", "eta"}
+	var c toyCollation
+	ByStringCollated(StringSlice(words), c.key)
+	got := fmt.Sprintf("%q", words)
+	want := fmt.Sprintf("%q", []string{"alpha", "eta", "étage", "Eté", "ete", "été", "Zebra", "zèbre"})
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		ByStringCollated(StringSlice(data), c.key)
+		if !sort.SliceIsSorted(data, func(i, j int) bool {
+			ki := string(c.key(data[i]))
+			return ki < string(c.key(data[j])) || ki == string(c.key(data[j])) && data[i] < data[j]
+		}) {
+			t.Errorf("didn't sort")
+		}
+	})
+}
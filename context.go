@@ -0,0 +1,107 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+)
+
+// contextSort runs a sort through next, dropping every task that starts
+// after ctx is done.  Tasks are radix passes and quicksort partitions, so
+// the check is once per pass over a range, not per item.
+type contextSort struct {
+	done    <-chan struct{}
+	next    sortRunner
+	dropped int32
+}
+
+// newContextSort returns a contextSort for ctx running sorts through next.
+func newContextSort(ctx context.Context, next sortRunner) *contextSort {
+	return &contextSort{done: ctx.Done(), next: next}
+}
+
+// run is a sortRunner.
+func (c *contextSort) run(data sort.Interface, sorter sortFunc, initialTask task) {
+	c.next(data, func(data sort.Interface, t task, sortRange func(task)) {
+		select {
+		case <-c.done:
+			atomic.StoreInt32(&c.dropped, 1)
+			return
+		default:
+		}
+		sorter(data, t, sortRange)
+	}, initialTask)
+}
+
+// err is ctx.Err() if the sort was cut short, or else whatever err the
+// sort returned, which is panicked on as the By* functions do.
+func (c *contextSort) err(ctx context.Context, err error) error {
+	if atomic.LoadInt32(&c.dropped) != 0 {
+		// don't report the check afterwards failing on unfinished work
+		return ctx.Err()
+	}
+	mustSort(err)
+	return nil
+}
+
+// ByUint64Context is ByUint64, but stops early and returns ctx.Err() if
+// ctx is done before the sort finishes, for sorts that may need to be
+// abandoned, like those serving a request.  ctx is checked before each
+// radix pass or quicksort partition, so a cancelled sort returns after at
+// most about one pass over the range it was working on.  After a
+// cancellation data is left partially sorted; the check afterwards soon
+// finds an item out of order and stops, and isn't reported.  A sort that
+// finishes returns nil even if ctx is done by then, and panics like
+// ByUint64 if the check fails.  Inputs too small to radix sort are just
+// quicksorted, without checking ctx.
+func ByUint64Context(ctx context.Context, data Uint64Interface) error {
+	c := newContextSort(ctx, parallelSort)
+	return c.err(ctx, byUint64(data, c.run, VerifyResults, nil))
+}
+
+// ByInt64Context is ByInt64, cancelled like ByUint64Context.
+func ByInt64Context(ctx context.Context, data Int64Interface) error {
+	c := newContextSort(ctx, parallelSort)
+	return c.err(ctx, byInt64(data, c.run, VerifyResults, nil))
+}
+
+// ByStringContext is ByString, cancelled like ByUint64Context.
+func ByStringContext(ctx context.Context, data StringInterface) error {
+	c := newContextSort(ctx, parallelSort)
+	return c.err(ctx, byString(data, c.run, VerifyResults, nil))
+}
+
+// ByBytesContext is ByBytes, cancelled like ByUint64Context.
+func ByBytesContext(ctx context.Context, data BytesInterface) error {
+	c := newContextSort(ctx, parallelSort)
+	return c.err(ctx, byBytes(data, c.run, VerifyResults, nil))
+}
+
+// ByUint64Context is the package-level ByUint64Context, within o's limits.
+func (o *Options) ByUint64Context(ctx context.Context, data Uint64Interface) error {
+	c := newContextSort(ctx, o.runner())
+	return c.err(ctx, byUint64(data, c.run, o.verify(), o.settings()))
+}
+
+// ByInt64Context is the package-level ByInt64Context, within o's limits.
+func (o *Options) ByInt64Context(ctx context.Context, data Int64Interface) error {
+	c := newContextSort(ctx, o.runner())
+	return c.err(ctx, byInt64(data, c.run, o.verify(), o.settings()))
+}
+
+// ByStringContext is the package-level ByStringContext, within o's limits.
+func (o *Options) ByStringContext(ctx context.Context, data StringInterface) error {
+	c := newContextSort(ctx, o.runner())
+	return c.err(ctx, byString(data, c.run, o.verify(), o.settings()))
+}
+
+// ByBytesContext is the package-level ByBytesContext, within o's limits.
+func (o *Options) ByBytesContext(ctx context.Context, data BytesInterface) error {
+	c := newContextSort(ctx, o.runner())
+	return c.err(ctx, byBytes(data, c.run, o.verify(), o.settings()))
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// cancellingKeys calls cancel once Key has been called after times.
+type cancellingKeys struct {
+	Uint64Slice
+	calls  *int64
+	after  int64
+	cancel func()
+}
+
+func (p cancellingKeys) Key(i int) uint64 {
+	if atomic.AddInt64(p.calls, 1) == p.after {
+		p.cancel()
+	}
+	return p.Uint64Slice[i]
+}
+
+func TestContextCancel(t *testing.T) {
+	n := 1 << 20
+	data := make(Uint64Slice, n)
+	for i := range data {
+		data[i] = uint64(rand.Int63())
+	}
+
+	// cancelled halfway through the first counting pass, the sort should
+	// give up once that pass's swapping is done
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int64
+	err := ByUint64Context(ctx, cancellingKeys{data, &calls, int64(n / 2), cancel})
+	if err != context.Canceled {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if calls > int64(3*n) {
+		t.Errorf("%d Key calls for %d items after cancelling", calls, n)
+	}
+
+	// cancelled the same way, through Options
+	ctx, cancel = context.WithCancel(context.Background())
+	calls = 0
+	opts := &Options{MaxProcs: 1, QSortCutoff: 64}
+	err = opts.ByUint64Context(ctx, cancellingKeys{data, &calls, int64(n / 2), cancel})
+	if err != context.Canceled {
+		t.Errorf("Options: got %v, want context.Canceled", err)
+	}
+	if calls > int64(3*n) {
+		t.Errorf("Options: %d Key calls for %d items after cancelling", calls, n)
+	}
+
+	// already cancelled
+	strs := make([]string, n)
+	for i := range strs {
+		strs[i] = string(rune('a' + rand.Intn(26)))
+	}
+	if err := ByStringContext(ctx, StringSlice(strs)); err != context.Canceled {
+		t.Errorf("strings: got %v, want context.Canceled", err)
+	}
+
+	// not cancelled
+	if err := ByUint64Context(context.Background(), data); err != nil {
+		t.Errorf("uncancelled sort returned %v", err)
+	}
+	if !Uint64sAreSorted(data) {
+		t.Error("uncancelled sort didn't sort")
+	}
+	rand.Shuffle(n, data.Swap)
+	if err := opts.ByUint64Context(context.Background(), data); err != nil || !Uint64sAreSorted(data) {
+		t.Errorf("uncancelled Options sort returned %v or didn't sort", err)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// descUint64 reverses a Uint64Interface's order, complementing its keys
+// so the ordinary ascending radix sort puts the largest first.
+type descUint64 struct{ Uint64Interface }
+
+func (d descUint64) Key(i int) uint64   { return ^d.Uint64Interface.Key(i) }
+func (d descUint64) Less(i, j int) bool { return d.Uint64Interface.Less(j, i) }
+
+// descInt64 is descUint64 for Int64Interface; ^k is -k-1, which reverses
+// the order of int64s without overflowing.
+type descInt64 struct{ Int64Interface }
+
+func (d descInt64) Key(i int) int64    { return ^d.Int64Interface.Key(i) }
+func (d descInt64) Less(i, j int) bool { return d.Int64Interface.Less(j, i) }
+
+// ByUint64Descending sorts data by a uint64 key, largest first.  It's one
+// radix sort, not an ascending sort plus Flip, so Swap sees only the
+// moves the sort needs.  As with ByUint64, the result is checked, and a
+// Less that disagrees with Key causes a panic.
+func ByUint64Descending(data Uint64Interface) {
+	ByUint64(descUint64{data})
+}
+
+// ByInt64Descending sorts data by an int64 key, largest first, like
+// ByUint64Descending.
+func ByInt64Descending(data Int64Interface) {
+	ByInt64(descInt64{data})
+}
+
+// ByStringDescending sorts data by a string key in reverse byte order, so
+// a key that's a prefix of others ("app" for "apple") sorts after them.
+// The order comes from the keys; data.Less is only called to check the
+// result afterwards (if VerifyResults is set), and it panics if Less
+// disagrees with the keys.
+func ByStringDescending(data StringInterface) {
+	byStringOrdered(data, descendingOrder)
+	if !VerifyResults {
+		return
+	}
+	for i, l := 1, data.Len(); i < l; i++ {
+		if data.Less(i-1, i) {
+			panic(keyPanicMessage)
+		}
+	}
+}
+
+// ByBytesDescending is ByStringDescending for []byte keys.
+func ByBytesDescending(data BytesInterface) {
+	byBytesOrdered(data, descendingOrder)
+	if !VerifyResults {
+		return
+	}
+	for i, l := 1, data.Len(); i < l; i++ {
+		if data.Less(i-1, i) {
+			panic(keyPanicMessage)
+		}
+	}
+}
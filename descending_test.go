@@ -0,0 +1,66 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByUint64Descending(t *testing.T) {
+	varyQSortCutoff(func() {
+		u := make([]uint64, 10000)
+		for i := range u {
+			u[i] = uint64(rand.Int63n(1 << 20))
+		}
+		u[0], u[1] = 0, math.MaxUint64
+		ByUint64Descending(Uint64Slice(u))
+		if !sort.IsSorted(sort.Reverse(Uint64Slice(u))) {
+			t.Errorf("ByUint64Descending didn't sort")
+		}
+
+		n := make([]int64, 10000)
+		for i := range n {
+			n[i] = rand.Int63n(1<<20) - 1<<19
+		}
+		n[0], n[1] = math.MinInt64, math.MaxInt64
+		ByInt64Descending(Int64Slice(n))
+		if !sort.IsSorted(sort.Reverse(Int64Slice(n))) {
+			t.Errorf("ByInt64Descending didn't sort")
+		}
+	})
+}
+
+func TestByStringDescending(t *testing.T) {
+	words := []string{"app", "b", "", "apple", "apply", "ba"}
+	ByStringDescending(StringSlice(words))
+	if got, want := fmt.Sprint(words), "[ba b apply apple app ]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		ByStringDescending(StringSlice(data))
+		if !sort.IsSorted(sort.Reverse(StringSlice(data))) {
+			t.Errorf("ByStringDescending didn't sort")
+		}
+
+		b := make([][]byte, len(data))
+		for i, s := range randomWords(len(b)) {
+			b[i] = []byte(s)
+		}
+		ByBytesDescending(BytesSlice(b))
+		if !sort.IsSorted(sort.Reverse(BytesSlice(b))) {
+			t.Errorf("ByBytesDescending didn't sort")
+		}
+	})
+}
@@ -13,7 +13,11 @@ func Heapsort(data sort.Interface) {
 }
 
 func GuessIntShift(data Int64Interface, l int) uint {
-	return guessIntShift(intwrapper{data}, l)
+	return guessIntShift(intwrapper{data}, 0, l)
+}
+
+func GuessUint64Shift(data Uint64Interface, l int) uint {
+	return guessIntShift(data, 0, l)
 }
 
 func SetQSortCutoff(i int) int {
@@ -22,6 +26,12 @@ func SetQSortCutoff(i int) int {
 	return orig
 }
 
+func SetInsertionCutoff(i int) int {
+	orig := insertionCutoff
+	insertionCutoff = i
+	return orig
+}
+
 func SetMinOffload(i int) int {
 	orig := minOffload
 	minOffload = i
@@ -31,3 +41,29 @@ func SetMinOffload(i int) int {
 func Checking() bool {
 	return true
 }
+
+func SetWideRadixMin(i int) int {
+	orig := wideRadixMin
+	wideRadixMin = i
+	return orig
+}
+
+func SetPresortSamples(i int) int {
+	orig := presortSamples
+	presortSamples = i
+	return orig
+}
+
+func GuessInt32Shift(a []int32) uint {
+	return guessIntShift(newIntSlice(a), 0, len(a))
+}
+
+func SetMinParallelCount(i int) int {
+	orig := minParallelCount
+	minParallelCount = i
+	return orig
+}
+
+func Workers(l int) int {
+	return workers(l)
+}
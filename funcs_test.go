@@ -0,0 +1,184 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+func recordID(r record) uint64 { return r.id }
+
+func TestSortFunc(t *testing.T) {
+	checkRecordSorts(t, "SortFunc", func(recs []record) { SortFunc(recs, recordID) }, byID)
+}
+
+func BenchmarkSortFunc(b *testing.B) {
+	benchSlice(b, func(recs []record) { SortFunc(recs, recordID) })
+}
+
+func BenchmarkSortSlice(b *testing.B) {
+	benchSlice(b, func(recs []record) {
+		sort.Slice(recs, func(i, j int) bool { return recs[i].id < recs[j].id })
+	})
+}
+
+func recordName(r record) string { return r.name }
+
+func TestSortStringFunc(t *testing.T) {
+	checkRecordSorts(t, "SortStringFunc", func(recs []record) { SortStringFunc(recs, recordName) }, byName)
+}
+
+func TestSortStableFunc(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		// records numbered in order, keyed by a few values
+		recs := make([]record, n)
+		for i := range recs {
+			recs[i] = record{uint64(i), strconv.Itoa(rand.Intn(10))}
+		}
+		byDigit := func(r record) uint64 { return uint64(r.name[0]) }
+
+		SortStableFunc(recs, byDigit)
+		for i := 1; i < n; i++ {
+			a, b := recs[i-1], recs[i]
+			if a.name > b.name || a.name == b.name && a.id > b.id {
+				t.Fatalf("n=%d: SortStableFunc: records %d and %d out of order: %v, %v", n, i-1, i, a, b)
+			}
+		}
+
+		sort.Slice(recs, func(i, j int) bool { return recs[i].id < recs[j].id })
+		SortStableStringFunc(recs, recordName)
+		for i := 1; i < n; i++ {
+			a, b := recs[i-1], recs[i]
+			if a.name > b.name || a.name == b.name && a.id > b.id {
+				t.Fatalf("n=%d: SortStableStringFunc: records %d and %d out of order: %v, %v", n, i-1, i, a, b)
+			}
+		}
+	}
+}
+
+type hashed struct {
+	sum [8]byte
+	id  int
+}
+
+func TestSortBytesFunc(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		recs := make([]*hashed, n)
+		for i := range recs {
+			recs[i] = &hashed{id: i}
+			binary.BigEndian.PutUint64(recs[i].sum[:], rand.Uint64()>>uint(rand.Intn(64)))
+		}
+		varyQSortCutoff(func() {
+			SortBytesFunc(recs, func(h *hashed) []byte { return h.sum[:] })
+			for i := 1; i < n; i++ {
+				if bytes.Compare(recs[i].sum[:], recs[i-1].sum[:]) < 0 {
+					t.Fatalf("n=%d: sums out of order at %d", n, i)
+				}
+			}
+		})
+	}
+}
+
+func TestSortUint128Func(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		keys := make([][2]uint64, n)
+		for i := range keys {
+			// few distinct high words, so the low words get sorted too
+			keys[i] = [2]uint64{uint64(rand.Intn(10)), rand.Uint64()}
+		}
+		varyQSortCutoff(func() {
+			SortUint128Func(keys, func(k [2]uint64) (hi, lo uint64) { return k[0], k[1] })
+			for i := 1; i < n; i++ {
+				a, b := keys[i-1], keys[i]
+				if b[0] < a[0] || b[0] == a[0] && b[1] < a[1] {
+					t.Fatalf("n=%d: keys out of order at %d", n, i)
+				}
+			}
+		})
+	}
+}
+
+type fixedKeys struct {
+	k8  [8]byte
+	k16 [16]byte
+	k32 [32]byte
+}
+
+// fill randomizes b, giving every other item a common first half so the
+// later levels get sorted too.
+func fill(b []byte, i int) {
+	rand.Read(b)
+	if i%2 == 0 {
+		for j := range b[:len(b)/2] {
+			b[j] = 7
+		}
+	}
+}
+
+func TestSortFixed(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		recs := make([]fixedKeys, n)
+		for i := range recs {
+			fill(recs[i].k8[:], i)
+			fill(recs[i].k16[:], i)
+			fill(recs[i].k32[:], i)
+		}
+		sorted := func(key func(i int) []byte) bool {
+			for i := 1; i < n; i++ {
+				if bytes.Compare(key(i), key(i-1)) < 0 {
+					return false
+				}
+			}
+			return true
+		}
+		varyQSortCutoff(func() {
+			SortFixed(recs, func(r fixedKeys) [8]byte { return r.k8 })
+			if !sorted(func(i int) []byte { return recs[i].k8[:] }) {
+				t.Fatalf("n=%d: [8]byte keys didn't sort", n)
+			}
+			SortFixed(recs, func(r fixedKeys) [16]byte { return r.k16 })
+			if !sorted(func(i int) []byte { return recs[i].k16[:] }) {
+				t.Fatalf("n=%d: [16]byte keys didn't sort", n)
+			}
+			SortFixed(recs, func(r fixedKeys) [32]byte { return r.k32 })
+			if !sorted(func(i int) []byte { return recs[i].k32[:] }) {
+				t.Fatalf("n=%d: [32]byte keys didn't sort", n)
+			}
+		})
+	}
+}
+
+func benchFixed(b *testing.B, sorter func([]fixedKeys)) {
+	b.StopTimer()
+	recs := make([]fixedKeys, 1<<16)
+	for i := 0; i < b.N; i++ {
+		for i := range recs {
+			rand.Read(recs[i].k16[:])
+		}
+		b.StartTimer()
+		sorter(recs)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortFixed(b *testing.B) {
+	benchFixed(b, func(recs []fixedKeys) {
+		SortFixed(recs, func(r fixedKeys) [16]byte { return r.k16 })
+	})
+}
+
+func BenchmarkSortBytesFuncFixed(b *testing.B) {
+	benchFixed(b, func(recs []fixedKeys) {
+		SortBytesFunc(recs, func(r fixedKeys) []byte { return r.k16[:] })
+	})
+}
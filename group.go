@@ -0,0 +1,34 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// GroupByUint64 sorts data with ByUint64, then calls fn once per run of
+// equal items, in order, with the run's key, the index of its first item,
+// and its length.  Items are equal when neither is Less than the other,
+// and key is the Key of the run's first item.  It's the usual first step
+// of an aggregation: fn can total up data[start:start+count].
+func GroupByUint64(data Uint64Interface, fn func(key uint64, start, count int)) {
+	ByUint64(data)
+	GroupSortedUint64(data, fn)
+}
+
+// GroupSortedUint64 is GroupByUint64 for data that's already sorted: it
+// only walks the runs.  If data isn't sorted, equal items that aren't
+// adjacent are reported as separate runs.
+func GroupSortedUint64(data Uint64Interface, fn func(key uint64, start, count int)) {
+	l := data.Len()
+	if l == 0 {
+		return
+	}
+	start := 0
+	for i := 1; i < l; i++ {
+		if data.Less(start, i) || data.Less(i, start) {
+			fn(data.Key(start), start, i-start)
+			start = i
+		}
+	}
+	fn(data.Key(start), start, l-start)
+}
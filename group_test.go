@@ -0,0 +1,48 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestGroupByUint64(t *testing.T) {
+	data := make([]uint64, 10000)
+	counts := map[uint64]int{}
+	for i := range data {
+		data[i] = uint64(rand.Intn(100))
+		counts[data[i]]++
+	}
+	next := 0
+	GroupByUint64(Uint64Slice(data), func(key uint64, start, count int) {
+		if start != next {
+			t.Fatalf("run for %d starts at %d, want %d", key, start, next)
+		}
+		if count != counts[key] {
+			t.Fatalf("run for %d has %d items, want %d", key, count, counts[key])
+		}
+		for _, v := range data[start : start+count] {
+			if v != key {
+				t.Fatalf("run for %d holds %d", key, v)
+			}
+		}
+		delete(counts, key)
+		next = start + count
+	})
+	if next != len(data) || len(counts) != 0 {
+		t.Errorf("runs covered %d items, missed %d keys", next, len(counts))
+	}
+
+	calls := 0
+	GroupSortedUint64(Uint64Slice(nil), func(uint64, int, int) { calls++ })
+	if calls != 0 {
+		t.Errorf("called fn %d times for empty data", calls)
+	}
+}
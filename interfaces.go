@@ -44,6 +44,49 @@ type BytesInterface interface {
 	Key(i int) []byte
 }
 
+// MultiUint64Interface represents a collection that can be sorted by
+// several uint64 keys in turn, like the columns in "ORDER BY a, b, c": the
+// key at each level only breaks ties left by the levels before it.
+type MultiUint64Interface interface {
+	// Len is the number of elements in the collection.
+	Len() int
+	// Swap swaps the elements with indexes i and j.
+	Swap(i, j int)
+	// Levels is the number of keys each element has.
+	Levels() int
+	// Key provides element i's key at the given level, from 0 to
+	// Levels()-1.
+	Key(i, level int) uint64
+}
+
+// Uint64StringInterface represents a collection that can be sorted by a
+// uint64 key, with ties broken by a string key.
+type Uint64StringInterface interface {
+	// Len is the number of elements in the collection.
+	Len() int
+	// Swap swaps the elements with indexes i and j.
+	Swap(i, j int)
+	// Key provides the primary, uint64 key for element i.
+	Key(i int) uint64
+	// StringKey provides the string key that orders elements with equal
+	// Keys.
+	StringKey(i int) string
+}
+
+// Uint128Interface represents a collection that can be sorted by a
+// 128-bit unsigned key, such as a UUID or a 128-bit hash, given as two
+// uint64 halves.
+type Uint128Interface interface {
+	// Len is the number of elements in the collection.
+	Len() int
+	// Swap swaps the elements with indexes i and j.
+	Swap(i, j int)
+	// KeyHi provides the high 64 bits of element i's key.
+	KeyHi(i int) uint64
+	// KeyLo provides the low 64 bits of element i's key.
+	KeyLo(i int) uint64
+}
+
 // Flip reverses the order of items in a sort.Interface.
 func Flip(data sort.Interface) {
 	a, b := 0, data.Len()-1
@@ -0,0 +1,53 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "bytes"
+
+// IsSortedByUint64 reports whether data is in the order ByUint64 would
+// leave it in: no item is Less than the one before it, and no item's Key
+// is below the one before it.  Where the check after a sort would panic
+// because Key and Less disagree, this just returns false.  It only calls
+// Len, Less and Key, never Swap, so it's safe on data others are reading.
+func IsSortedByUint64(data Uint64Interface) bool {
+	for i := data.Len() - 1; i > 0; i-- {
+		if data.Less(i, i-1) || data.Key(i) < data.Key(i-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedByInt64 is IsSortedByUint64 for Int64Interface.
+func IsSortedByInt64(data Int64Interface) bool {
+	for i := data.Len() - 1; i > 0; i-- {
+		if data.Less(i, i-1) || data.Key(i) < data.Key(i-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedByString is IsSortedByUint64 for StringInterface.
+func IsSortedByString(data StringInterface) bool {
+	for i := data.Len() - 1; i > 0; i-- {
+		if data.Less(i, i-1) || data.Key(i) < data.Key(i-1) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedByBytes is IsSortedByUint64 for BytesInterface, comparing keys
+// with bytes.Compare.
+func IsSortedByBytes(data BytesInterface) bool {
+	for i := data.Len() - 1; i > 0; i-- {
+		if data.Less(i, i-1) || bytes.Compare(data.Key(i), data.Key(i-1)) < 0 {
+			return false
+		}
+	}
+	return true
+}
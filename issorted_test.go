@@ -0,0 +1,63 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestIsSorted(t *testing.T) {
+	tests := []struct {
+		name string
+		ints []int
+		want bool
+	}{
+		{"empty", nil, true},
+		{"single", []int{3}, true},
+		{"sorted", []int{-2, 1, 1, 5}, true},
+		{"reverse", []int{5, 1, 1, -2}, false},
+	}
+	for _, tt := range tests {
+		ints := append([]int(nil), tt.ints...)
+		uints := make([]uint, len(ints))
+		strs := make([]string, len(ints))
+		bs := make([][]byte, len(ints))
+		for i, v := range ints {
+			uints[i] = uint(v + 10)
+			strs[i] = string(rune('a' + v + 10))
+			bs[i] = []byte(strs[i])
+		}
+		if got := IsSortedByInt64(IntSlice(ints)); got != tt.want {
+			t.Errorf("%s: IsSortedByInt64 = %v", tt.name, got)
+		}
+		if got := IsSortedByUint64(UintSlice(uints)); got != tt.want {
+			t.Errorf("%s: IsSortedByUint64 = %v", tt.name, got)
+		}
+		if got := IsSortedByString(StringSlice(strs)); got != tt.want {
+			t.Errorf("%s: IsSortedByString = %v", tt.name, got)
+		}
+		if got := IsSortedByBytes(BytesSlice(bs)); got != tt.want {
+			t.Errorf("%s: IsSortedByBytes = %v", tt.name, got)
+		}
+	}
+
+	// in order by Less, but not by Key
+	if IsSortedByInt64(miskeyedInts{IntSlice{3, 2, 1}}) {
+		t.Error("miskeyed ints reported sorted")
+	}
+	if IsSortedByUint64(miskeyedUints{UintSlice{3, 2, 1}}) {
+		t.Error("miskeyed uints reported sorted")
+	}
+	if IsSortedByString(miskeyedStrings{StringSlice{"c", "b", "a"}}) {
+		t.Error("miskeyed strings reported sorted")
+	}
+	if IsSortedByBytes(miskeyedBytes{BytesSlice{{3}, {2}, {1}}}) {
+		t.Error("miskeyed bytes reported sorted")
+	}
+}
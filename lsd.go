@@ -0,0 +1,186 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sync"
+
+// lsdItem is a key and the index it came from.
+type lsdItem struct {
+	key uint64
+	idx int
+}
+
+// lsdCounts counts how many keys have each value of each byte.
+type lsdCounts [64 / radix][1 << radix]int
+
+// keys copies the keys of items lo through hi-1 of data into items,
+// counting their bytes.
+func (c *lsdCounts) keys(data Uint64Interface, items []lsdItem, lo, hi int) {
+	for i := lo; i < hi; i++ {
+		k := data.Key(i)
+		items[i] = lsdItem{k, i}
+		for d := range c {
+			c[d][(k>>uint(d*radix))&mask]++
+		}
+	}
+}
+
+// lsdPassParallel is one pass of ByUint64LSD, by the byte at shift, from
+// items into buf, split into w stripes of items.  Each stripe counts its
+// own bytes, then gets its own place in every bucket, after the stripes
+// before it, so it can scatter its items with no locking and the pass is
+// still stable.
+func lsdPassParallel(items, buf []lsdItem, shift uint, w int) {
+	offsets := make([][1 << radix]int, w)
+	inStripes(0, len(items), w, func(s, lo, hi int) {
+		c := &offsets[s]
+		for _, it := range items[lo:hi] {
+			c[(it.key>>shift)&mask]++
+		}
+	})
+	pos := 0
+	for b := 0; b < 1<<radix; b++ {
+		for s := range offsets {
+			n := offsets[s][b]
+			offsets[s][b] = pos
+			pos += n
+		}
+	}
+	inStripes(0, len(items), w, func(s, lo, hi int) {
+		c := &offsets[s]
+		for _, it := range items[lo:hi] {
+			b := (it.key >> shift) & mask
+			buf[c[b]] = it
+			c[b]++
+		}
+	})
+}
+
+// lsdBuffers is the scratch memory of an LSD sort, kept in lsdPool
+// between sorts.
+type lsdBuffers struct {
+	items, buf []lsdItem
+	idx        []int
+}
+
+// lsdPool holds the buffers of finished LSD sorts for later ones to
+// reuse, so sorting often doesn't allocate every time.
+var lsdPool = sync.Pool{New: func() interface{} { return new(lsdBuffers) }}
+
+// grow returns buffers for sorting n items, reallocating b's if they're
+// too small.
+func (b *lsdBuffers) grow(n int) (items, buf []lsdItem, idx []int) {
+	if cap(b.items) < n {
+		b.items, b.buf, b.idx = make([]lsdItem, n), make([]lsdItem, n), make([]int, n)
+	}
+	return b.items[:n], b.buf[:n], b.idx[:n]
+}
+
+// ByUint64LSD sorts data by a uint64 key with a least-significant-digit
+// radix sort, keeping items with equal keys in their input order.  Where
+// ByUint64 swaps items in place, bucket by bucket from the top byte down,
+// this copies every key out once, then sorts the keys and their original
+// indices out of place, a byte at a time from the bottom, with sequential
+// reads and writes instead of scattered swaps.  Bytes that are the same
+// in every key are skipped.  data's items are then moved into place with
+// about one Swap each, following the cycles of the final permutation.
+//
+// It uses O(n) extra memory: five words (40 bytes on 64-bit platforms)
+// per item, for two buffers of keys with their indices and the final
+// permutation.  The buffers are pooled, so a program sorting repeatedly
+// mostly reuses them instead of allocating.  With more than one worker
+// (see MaxProcs), copying out the keys and each pass are split into
+// stripes handled at once, each with its own bucket offsets; the final
+// Swaps are serial.  Don't expect it to beat ByUint64: on 10 million
+// random keys (BenchmarkByUint64LSD1e7 in lsd_test.go) it took about
+// three times as long, half of that in the final Swaps, which jump all
+// over data just as the in-place passes do.  Use it when equal keys must
+// stay in input order.  data.Less is only called to check the result.
+func ByUint64LSD(data Uint64Interface) {
+	b := lsdPool.Get().(*lsdBuffers)
+	b.sort(data)
+	lsdPool.Put(b)
+}
+
+// ByInt64LSD is ByUint64LSD for int64 keys, equally stable.
+func ByInt64LSD(data Int64Interface) {
+	b := lsdPool.Get().(*lsdBuffers)
+	b.sort(intwrapper{data})
+	lsdPool.Put(b)
+}
+
+// sort is ByUint64LSD using b's buffers.
+func (b *lsdBuffers) sort(data Uint64Interface) {
+	l := data.Len()
+	if l < qSortCutoff {
+		// stable, unlike qSort
+		insertionSort(data, 0, l)
+		return
+	}
+
+	items, buf, idx := b.grow(l)
+	w := workers(l)
+	var counts lsdCounts
+	if w > 1 {
+		// first is never reassigned, so the closure copies it and items
+		// can stay on the stack
+		stripeCounts, first := make([]lsdCounts, w), items
+		inStripes(0, l, w, func(s, lo, hi int) {
+			stripeCounts[s].keys(data, first, lo, hi)
+		})
+		for s := range stripeCounts {
+			for d := range counts {
+				for b, n := range stripeCounts[s][d] {
+					counts[d][b] += n
+				}
+			}
+		}
+	} else {
+		counts.keys(data, items, 0, l)
+	}
+
+	for d := range counts {
+		shift := uint(d * radix)
+		c := &counts[d]
+		if c[(items[0].key>>shift)&mask] == l {
+			// every key has the same byte here
+			continue
+		}
+		if w > 1 {
+			lsdPassParallel(items, buf, shift, w)
+			items, buf = buf, items
+			continue
+		}
+		pos := 0
+		for b, n := range c {
+			c[b] = pos
+			pos += n
+		}
+		for _, it := range items {
+			b := (it.key >> shift) & mask
+			buf[c[b]] = it
+			c[b]++
+		}
+		items, buf = buf, items
+	}
+	for i, it := range items {
+		idx[i] = it.idx
+	}
+	permute(data, idx)
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if data.Less(i, i-1) {
+			if data.Key(i) > data.Key(i-1) {
+				panic(keyPanicMessage + keyUint64Help)
+			}
+			panic(panicMessage)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// taggedUint64s are keys tagged with their original position.
+type taggedUint64s struct {
+	keys []uint64
+	tags []int
+}
+
+func (p taggedUint64s) Len() int           { return len(p.keys) }
+func (p taggedUint64s) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p taggedUint64s) Swap(i, j int) {
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+	p.tags[i], p.tags[j] = p.tags[j], p.tags[i]
+}
+func (p taggedUint64s) Key(i int) uint64 { return p.keys[i] }
+
+// taggedInt64s are taggedUint64s read as signed keys.
+type taggedInt64s struct{ taggedUint64s }
+
+func (p taggedInt64s) Less(i, j int) bool { return p.Key(i) < p.Key(j) }
+func (p taggedInt64s) Key(i int) int64    { return int64(p.keys[i]) }
+
+func TestByInt64LSD(t *testing.T) {
+	for _, n := range []int{0, 100, 10000} {
+		// few distinct keys, negative and positive
+		p := taggedInt64s{taggedUint64s{make([]uint64, n), make([]int, n)}}
+		for i := range p.keys {
+			p.keys[i] = uint64(rand.Intn(16)-8)<<56 | uint64(rand.Intn(4))
+			p.tags[i] = i
+		}
+		ByInt64LSD(p)
+		for i := 1; i < n; i++ {
+			ki, kj := p.Key(i-1), p.Key(i)
+			if ki > kj || ki == kj && p.tags[i-1] > p.tags[i] {
+				t.Fatalf("n=%d: items %d and %d out of order: %x/%d, %x/%d", n, i-1, i, ki, p.tags[i-1], kj, p.tags[i])
+			}
+		}
+	}
+}
+
+func TestByUint64LSD(t *testing.T) {
+	// 4 procs splits the passes of the sorts big enough to go parallel
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for _, procs := range []int{1, 4} {
+		runtime.GOMAXPROCS(procs)
+		testByUint64LSD(t)
+	}
+}
+
+func testByUint64LSD(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000, 30000} {
+		// few distinct keys, spread over the high and low bytes
+		p := taggedUint64s{make([]uint64, n), make([]int, n)}
+		for i := range p.keys {
+			p.keys[i] = uint64(rand.Intn(16))<<60 | uint64(rand.Intn(4))
+			p.tags[i] = i
+		}
+		ByUint64LSD(p)
+		for i := 1; i < n; i++ {
+			ki, kj := p.keys[i-1], p.keys[i]
+			if ki > kj || ki == kj && p.tags[i-1] > p.tags[i] {
+				t.Fatalf("n=%d: items %d and %d out of order: %x/%d, %x/%d", n, i-1, i, ki, p.tags[i-1], kj, p.tags[i])
+			}
+		}
+
+		data := make([]uint64, n)
+		for i := range data {
+			data[i] = uint64(rand.Int63())<<1 ^ uint64(rand.Intn(2))
+		}
+		ByUint64LSD(Uint64Slice(data))
+		if !Uint64sAreSorted(data) {
+			t.Errorf("n=%d: random keys didn't sort", n)
+		}
+	}
+}
+
+func benchLSD(b *testing.B, sorter func(Uint64Interface)) {
+	b.StopTimer()
+	data := make([]uint64, 1e7)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = uint64(rand.Int63())<<1 ^ uint64(rand.Intn(2))
+		}
+		b.StartTimer()
+		sorter(Uint64Slice(data))
+		b.StopTimer()
+	}
+}
+
+func BenchmarkByUint64LSD1e7(b *testing.B) { benchLSD(b, ByUint64LSD) }
+func BenchmarkByUint64MSD1e7(b *testing.B) { benchLSD(b, ByUint64) }
+
+func BenchmarkByUint64LSDSmall(b *testing.B) {
+	b.ReportAllocs()
+	data := make([]uint64, 1000)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = rand.Uint64()
+		}
+		ByUint64LSD(Uint64Slice(data))
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2009 The Go Authors.
+// Copyright 2015 Randall Farmer.
+// All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// symMerge, swapRange and rotate are copied from Go's sort.go, where they
+// implement the stable sort.
+
+// symMerge merges the two sorted subsequences data[a:m] and data[m:b] using
+// the SymMerge algorithm from Pok-Son Kim and Arne Kutzner, "Stable Minimum
+// Storage Merging by Symmetric Comparisons", in Susanne Albers and Tomasz
+// Radzik, editors, Algorithms - ESA 2004, volume 3221 of Lecture Notes in
+// Computer Science, pages 714-723. Springer, 2004.
+func symMerge(data sort.Interface, a, m, b int) {
+	if m-a == 1 {
+		// find the first position in data[m:b] not less than data[a]
+		i, j := m, b
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if data.Less(h, a) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := a; k < i-1; k++ {
+			data.Swap(k, k+1)
+		}
+		return
+	}
+	if b-m == 1 {
+		// find the first position in data[a:m] greater than data[m]
+		i, j := a, m
+		for i < j {
+			h := int(uint(i+j) >> 1)
+			if !data.Less(m, h) {
+				i = h + 1
+			} else {
+				j = h
+			}
+		}
+		for k := m; k > i; k-- {
+			data.Swap(k, k-1)
+		}
+		return
+	}
+
+	mid := int(uint(a+b) >> 1)
+	n := mid + m
+	var start, r int
+	if m > mid {
+		start = n - b
+		r = mid
+	} else {
+		start = a
+		r = m
+	}
+	p := n - 1
+
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if !data.Less(p-c, c) {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+
+	end := n - start
+	if start < m && m < end {
+		rotate(data, start, m, end)
+	}
+	if a < start && start < mid {
+		symMerge(data, a, start, mid)
+	}
+	if mid < end && end < b {
+		symMerge(data, mid, end, b)
+	}
+}
+
+// swapRange swaps data[a:a+n] with data[b:b+n].
+func swapRange(data sort.Interface, a, b, n int) {
+	for i := 0; i < n; i++ {
+		data.Swap(a+i, b+i)
+	}
+}
+
+// rotate rotates two consecutive blocks u = data[a:m] and v = data[m:b],
+// leaving v before u.
+func rotate(data sort.Interface, a, m, b int) {
+	i := m - a
+	j := b - m
+
+	for i != j {
+		if i > j {
+			swapRange(data, m-i, m, j)
+			i -= j
+		} else {
+			swapRange(data, m-i, m+j-i, i)
+			j -= i
+		}
+	}
+	// i == j
+	swapRange(data, m-i, m, i)
+}
+
+const mergeBoundsMessage = "MergeUint64: boundaries must be strictly increasing and within [0, Len()]"
+
+// MergeUint64 sorts data that's made up of runs that are each already
+// sorted: data[0:boundaries[0]], data[boundaries[0]:boundaries[1]], and so
+// on, the last run ending at data.Len().  It merges neighboring runs in
+// place, using only Less and Swap, until one run is left.  That's
+// O(n log n log k) swaps for n items in k runs, with no extra memory, and
+// it keeps equal items in order.  It isn't usually faster than sorting
+// everything again with ByUint64, though: merging in place with nothing
+// but Swap moves items many times, and on 64K uint64s in 4 runs
+// BenchmarkMergeUint64 takes about twice as long as ByUint64.  Use it
+// when stability matters or Key is unavailable or costly.
+//
+// boundaries must be strictly increasing and between 0 and data.Len(),
+// or MergeUint64 panics.  Each run being sorted is the caller's
+// responsibility; if one isn't, the check afterwards panics.
+func MergeUint64(data Uint64Interface, boundaries []int) {
+	l := data.Len()
+	starts := make([]int, 1, len(boundaries)+2)
+	for i, b := range boundaries {
+		if b < 0 || b > l || i > 0 && b <= boundaries[i-1] {
+			panic(mergeBoundsMessage)
+		}
+		if b > 0 && b < l {
+			starts = append(starts, b)
+		}
+	}
+	starts = append(starts, l)
+
+	// starts[i]:starts[i+1] are the runs; merge pairs until one is left
+	for len(starts) > 2 {
+		next := starts[:1]
+		for i := 0; i+1 < len(starts); i += 2 {
+			if i+2 < len(starts) {
+				symMerge(data, starts[i], starts[i+1], starts[i+2])
+				next = append(next, starts[i+2])
+			} else {
+				next = append(next, starts[i+1])
+			}
+		}
+		starts = next
+	}
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if data.Less(i, i-1) {
+			panic(panicMessage)
+		}
+	}
+}
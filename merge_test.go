@@ -0,0 +1,79 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// sortedRuns fills data with k sorted runs of random lengths and returns
+// the boundaries between them.
+func sortedRuns(data []uint64, k int) []int {
+	for i := range data {
+		data[i] = uint64(rand.Intn(len(data)))
+	}
+	var boundaries []int
+	for i := 1; i < k; i++ {
+		boundaries = append(boundaries, rand.Intn(len(data)+1))
+	}
+	Ints(boundaries)
+	uniq := boundaries[:0]
+	for _, b := range boundaries {
+		if len(uniq) == 0 || b != uniq[len(uniq)-1] {
+			uniq = append(uniq, b)
+		}
+	}
+	boundaries = uniq
+	start := 0
+	for _, b := range append(boundaries, len(data)) {
+		Uint64s(data[start:b])
+		start = b
+	}
+	return boundaries
+}
+
+func TestMergeUint64(t *testing.T) {
+	for _, k := range []int{1, 2, 3, 10, 100} {
+		data := make([]uint64, 10000)
+		boundaries := sortedRuns(data, k)
+		MergeUint64(Uint64Slice(data), boundaries)
+		if !Uint64sAreSorted(data) {
+			t.Errorf("k=%d: didn't sort", k)
+		}
+	}
+
+	data := Uint64Slice{1, 3, 2, 4}
+	MergeUint64(data, []int{0, 2, 4})
+	if !Uint64sAreSorted(data) {
+		t.Errorf("runs with empty ends didn't merge: %v", data)
+	}
+	for _, bad := range [][]int{{-1}, {5}, {2, 2}, {3, 1}} {
+		mustPanic(t, "bad boundaries", func() { MergeUint64(Uint64Slice{1, 2, 3, 4}, bad) })
+	}
+}
+
+func benchMerge(b *testing.B, sorter func(Uint64Slice, []int)) {
+	b.StopTimer()
+	data := make([]uint64, 1<<16)
+	for i := 0; i < b.N; i++ {
+		boundaries := sortedRuns(data, 4)
+		b.StartTimer()
+		sorter(data, boundaries)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkMergeUint64(b *testing.B) {
+	benchMerge(b, func(a Uint64Slice, boundaries []int) { MergeUint64(a, boundaries) })
+}
+
+func BenchmarkMergeByUint64(b *testing.B) {
+	benchMerge(b, func(a Uint64Slice, _ []int) { ByUint64(a) })
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// levelRange presents one level of keys for items a through a+n-1 of a
+// MultiUint64Interface as a Uint64Interface.  Keys are XORed with flip, so
+// a flip of all ones sorts the level descending.
+type levelRange struct {
+	data  MultiUint64Interface
+	level int
+	flip  uint64
+	a, n  int
+}
+
+func (d levelRange) Len() int           { return d.n }
+func (d levelRange) Swap(i, j int)      { d.data.Swap(d.a+i, d.a+j) }
+func (d levelRange) Key(i int) uint64   { return d.data.Key(d.a+i, d.level) ^ d.flip }
+func (d levelRange) Less(i, j int) bool { return d.Key(i) < d.Key(j) }
+
+// levelFlip returns the XOR mask that gives level its direction.
+func levelFlip(descending []bool, level int) uint64 {
+	if level < len(descending) && descending[level] {
+		return ^uint64(0)
+	}
+	return 0
+}
+
+// compareLevels compares items i and j of data level by level, each level
+// in its own direction, returning -1, 0, or 1.
+func compareLevels(data MultiUint64Interface, descending []bool, i, j int) int {
+	for level, levels := 0, data.Levels(); level < levels; level++ {
+		flip := levelFlip(descending, level)
+		ki, kj := data.Key(i, level)^flip, data.Key(j, level)^flip
+		if ki != kj {
+			if ki < kj {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortLevels sorts items a through b-1 of data by level and each level
+// after it.
+func sortLevels(data MultiUint64Interface, descending []bool, level, a, b int) {
+	flip := levelFlip(descending, level)
+	ByUint64(levelRange{data, level, flip, a, b - a})
+	if level+1 == data.Levels() {
+		return
+	}
+	// sort runs of equal keys by the next level
+	start, startKey := a, data.Key(a, level)
+	for i := a + 1; i <= b; i++ {
+		if i < b && data.Key(i, level) == startKey {
+			continue
+		}
+		if i-start > 1 {
+			sortLevels(data, descending, level+1, start, i)
+		}
+		if i < b {
+			start, startKey = i, data.Key(i, level)
+		}
+	}
+}
+
+// ByUint64s sorts data by each of its levels of keys in turn, with the
+// direction of each level set by descending: level k sorts descending if
+// descending[k] is true, and ascending if it's false or past the end of
+// descending.  That's SQL's "ORDER BY a ASC, b DESC" with a and b as
+// levels 0 and 1.
+//
+// Each level is radix sorted as ByUint64 sorts, then runs of items tied
+// at that level are sorted by the next.  Only the keys are consulted;
+// the result is checked afterwards, comparing each level in its own
+// direction, and a failed check panics.
+func ByUint64s(data MultiUint64Interface, descending []bool) {
+	l := data.Len()
+	if l < 2 || data.Levels() == 0 {
+		return
+	}
+	sortLevels(data, descending, 0, 0, l)
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if compareLevels(data, descending, i, i-1) < 0 {
+			panic(panicMessage)
+		}
+	}
+}
+
+// primaryKeys sorts a Uint64StringInterface by its uint64 keys alone.
+type primaryKeys struct{ Uint64StringInterface }
+
+func (d primaryKeys) Less(i, j int) bool { return d.Key(i) < d.Key(j) }
+
+// secondaryRange presents the string keys of items a through a+n-1 of a
+// Uint64StringInterface as a StringInterface.
+type secondaryRange struct {
+	data Uint64StringInterface
+	a, n int
+}
+
+func (d secondaryRange) Len() int           { return d.n }
+func (d secondaryRange) Swap(i, j int)      { d.data.Swap(d.a+i, d.a+j) }
+func (d secondaryRange) Key(i int) string   { return d.data.StringKey(d.a + i) }
+func (d secondaryRange) Less(i, j int) bool { return d.Key(i) < d.Key(j) }
+
+// ByUint64ThenString sorts data by its uint64 keys, and items with equal
+// uint64 keys by their string keys.  The uint64 sort leaves each run of
+// equal keys contiguous, so the string sort only has to visit those runs,
+// each one radix sorted as ByString would.  Only the keys are consulted,
+// and the result is checked afterwards.
+func ByUint64ThenString(data Uint64StringInterface) {
+	l := data.Len()
+	if l < 2 {
+		return
+	}
+	ByUint64(primaryKeys{data})
+	start, startKey := 0, data.Key(0)
+	for i := 1; i <= l; i++ {
+		if i < l && data.Key(i) == startKey {
+			continue
+		}
+		if i-start > 1 {
+			ByString(secondaryRange{data, start, i - start})
+		}
+		if i < l {
+			start, startKey = i, data.Key(i)
+		}
+	}
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		ki, kj := data.Key(i-1), data.Key(i)
+		if ki > kj || ki == kj && data.StringKey(i-1) > data.StringKey(i) {
+			panic(panicMessage)
+		}
+	}
+}
+
+// uint128Levels presents a Uint128Interface as two levels of keys, high
+// word first.
+type uint128Levels struct{ Uint128Interface }
+
+func (d uint128Levels) Levels() int { return 2 }
+func (d uint128Levels) Key(i, level int) uint64 {
+	if level == 0 {
+		return d.KeyHi(i)
+	}
+	return d.KeyLo(i)
+}
+
+// ByUint128 sorts data by its 128-bit keys, as ByUint64s sorts two levels:
+// the high words are radix sorted as ByUint64 would sort them, skipping
+// bits they all share, then each run of equal high words is radix sorted
+// by its low words, with no 16-byte []byte keys to build or compare.
+// The result is checked afterwards, comparing both words.
+func ByUint128(data Uint128Interface) {
+	ByUint64s(uint128Levels{data}, nil)
+}
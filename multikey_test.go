@@ -0,0 +1,125 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+// columns is a table of uint64s stored row by row, with one level per
+// column.
+type columns [][3]uint64
+
+func (c columns) Len() int                { return len(c) }
+func (c columns) Swap(i, j int)           { c[i], c[j] = c[j], c[i] }
+func (c columns) Levels() int             { return 3 }
+func (c columns) Key(i, level int) uint64 { return c[i][level] }
+
+func TestByUint64s(t *testing.T) {
+	small := columns{{1, 5, 0}, {0, 1, 0}, {1, 7, 0}, {0, 2, 0}, {1, 5, 1}}
+	ByUint64s(small, []bool{false, true})
+	if got, want := fmt.Sprint(small), "[[0 2 0] [0 1 0] [1 7 0] [1 5 0] [1 5 1]]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	for _, descending := range [][]bool{nil, {true}, {false, true}, {true, false, true}} {
+		data := make(columns, 10000)
+		for i := range data {
+			// few distinct values in the first columns, so later levels matter
+			data[i] = [3]uint64{uint64(rand.Intn(4)), uint64(rand.Intn(300)), uint64(rand.Int63())}
+		}
+		less := func(i, j int) bool {
+			for level := range data[i] {
+				a, b := data[i][level], data[j][level]
+				if a == b {
+					continue
+				}
+				if level < len(descending) && descending[level] {
+					return a > b
+				}
+				return a < b
+			}
+			return false
+		}
+		varyQSortCutoff(func() {
+			ByUint64s(data, descending)
+			if !sort.SliceIsSorted(data, less) {
+				t.Errorf("descending=%v: didn't sort", descending)
+			}
+			rand.Shuffle(len(data), data.Swap)
+		})
+	}
+}
+
+// users have IDs that collide often and distinct names.
+type users struct {
+	ids   []uint64
+	names []string
+}
+
+func (u users) Len() int               { return len(u.ids) }
+func (u users) Key(i int) uint64       { return u.ids[i] }
+func (u users) StringKey(i int) string { return u.names[i] }
+func (u users) Swap(i, j int) {
+	u.ids[i], u.ids[j] = u.ids[j], u.ids[i]
+	u.names[i], u.names[j] = u.names[j], u.names[i]
+}
+
+func TestByUint64ThenString(t *testing.T) {
+	varyQSortCutoff(func() {
+		n := 10000
+		u := users{make([]uint64, n), make([]string, n)}
+		for i := range u.ids {
+			u.ids[i] = uint64(rand.Intn(20))
+			u.names[i] = fmt.Sprint(rand.Int())
+		}
+		ByUint64ThenString(u)
+		for i := 1; i < n; i++ {
+			a, b := u.ids[i-1], u.ids[i]
+			if a > b || a == b && u.names[i-1] > u.names[i] {
+				t.Fatalf("out of order at %d: %d %q, %d %q", i, a, u.names[i-1], b, u.names[i])
+			}
+		}
+	})
+}
+
+// uuids are 128-bit keys stored as pairs of words.
+type uuids [][2]uint64
+
+func (u uuids) Len() int           { return len(u) }
+func (u uuids) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u uuids) KeyHi(i int) uint64 { return u[i][0] }
+func (u uuids) KeyLo(i int) uint64 { return u[i][1] }
+
+func TestByUint128(t *testing.T) {
+	varyQSortCutoff(func() {
+		n := 10000
+		data := make(uuids, n)
+		for i := range data {
+			data[i] = [2]uint64{uint64(rand.Int63()) << 1, uint64(rand.Int63()) << 1}
+			if i%2 == 0 {
+				// collide in the high word
+				data[i][0] = uint64(rand.Intn(4)) << 62
+			}
+			if i%4 == 0 {
+				data[i][1] |= 1
+			}
+		}
+		data[0] = [2]uint64{^uint64(0), ^uint64(0)}
+		ByUint128(data)
+		for i := 1; i < n; i++ {
+			a, b := data[i-1], data[i]
+			if a[0] > b[0] || a[0] == b[0] && a[1] > b[1] {
+				t.Fatalf("out of order at %d: %x, %x", i, a, b)
+			}
+		}
+	})
+}
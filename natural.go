@@ -0,0 +1,67 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "encoding/binary"
+
+// NaturalKey returns a key for s that sorts, bytewise, in natural order:
+// runs of decimal digits are compared by numeric value, so "file2" sorts
+// before "file10", and everything else by byte.  Each digit run becomes
+// a '0' byte, the count of its digits after any leading zeros (one byte,
+// or 0xff and then 8 big-endian bytes for runs of 255 digits or more),
+// and those digits, so a longer number sorts after a shorter one and
+// numbers of equal length by their digits.  A number compares with any
+// other byte as the digit '0' would.  Runs differing only in leading
+// zeros, like "07" and "7", get equal keys.
+func NaturalKey(s string) string {
+	key := make([]byte, 0, len(s)+4)
+	for i := 0; i < len(s); {
+		if !isDigit(s[i]) {
+			key = append(key, s[i])
+			i++
+			continue
+		}
+		for i < len(s) && s[i] == '0' {
+			i++
+		}
+		start := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if n := i - start; n < 0xff {
+			key = append(key, '0', byte(n))
+		} else {
+			key = append(key, '0', 0xff)
+			key = binary.BigEndian.AppendUint64(key, uint64(n))
+		}
+		key = append(key, s[start:i]...)
+	}
+	return string(key)
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// NaturalLess reports whether a sorts before b in natural order, by
+// NaturalKey, with ties (strings differing only in leading zeros) broken
+// by ordinary string order.  It builds both keys, so ByNaturalString,
+// which builds each key once, is much cheaper than sorting with it.
+func NaturalLess(a, b string) bool {
+	ka, kb := NaturalKey(a), NaturalKey(b)
+	if ka != kb {
+		return ka < kb
+	}
+	return a < b
+}
+
+// ByNaturalString sorts data by string key in natural order, so numbered
+// names like "file2" and "file10", "v1.9" and "v1.10", come out in
+// numeric order.  It's ByStringNormalized with NaturalKey: each key is
+// encoded once, up front, and the radix passes run over the encoded
+// keys.  Items whose keys tie under NaturalKey, like "a07" and "a7", are
+// ordered by data.Less.
+func ByNaturalString(data StringInterface) {
+	ByStringNormalized(data, NaturalKey)
+}
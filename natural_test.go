@@ -0,0 +1,55 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestNaturalKey(t *testing.T) {
+	// in increasing natural order
+	ordered := []string{
+		"", "-1", "0", "00", "01", "1", "2", "9", "10", "99", "100",
+		strings.Repeat("9", 254), strings.Repeat("1", 255), strings.Repeat("1", 300),
+		"a", "file", "file1", "file1a", "file2", "file02b", "file10", "file10.txt", "filea",
+		"v1.9", "v1.10", "v1.10.1", "v2",
+	}
+	for i := 1; i < len(ordered); i++ {
+		if !NaturalLess(ordered[i-1], ordered[i]) || NaturalLess(ordered[i], ordered[i-1]) {
+			t.Errorf("NaturalLess(%q, %q) is wrong", ordered[i-1], ordered[i])
+		}
+	}
+	if NaturalKey("a07") != NaturalKey("a7") {
+		t.Errorf("leading zeros changed the key")
+	}
+
+	shuffled := append([]string(nil), ordered...)
+	rand.Shuffle(len(shuffled), StringSlice(shuffled).Swap)
+	ByNaturalString(StringSlice(shuffled))
+	if got, want := fmt.Sprintf("%q", shuffled), fmt.Sprintf("%q", ordered); got != want {
+		t.Errorf("ByNaturalString: got %s, want %s", got, want)
+	}
+}
+
+func TestByNaturalString(t *testing.T) {
+	varyQSortCutoff(func() {
+		words := make([]string, 10000)
+		for i := range words {
+			words[i] = fmt.Sprintf("%s%d%s", randomWords(1)[0], rand.Intn(1000), randomWords(1)[0])
+		}
+		ByNaturalString(StringSlice(words))
+		if !sort.SliceIsSorted(words, func(i, j int) bool { return NaturalLess(words[i], words[j]) }) {
+			t.Errorf("didn't sort")
+		}
+	})
+}
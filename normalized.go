@@ -0,0 +1,61 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// normalizedStrings sorts a StringInterface by cached, normalized copies
+// of its keys, breaking ties with the original Less.
+type normalizedStrings struct {
+	data StringInterface
+	keys []string
+}
+
+func (p normalizedStrings) Len() int { return len(p.keys) }
+func (p normalizedStrings) Less(i, j int) bool {
+	if p.keys[i] != p.keys[j] {
+		return p.keys[i] < p.keys[j]
+	}
+	return p.data.Less(i, j)
+}
+func (p normalizedStrings) Swap(i, j int) {
+	p.data.Swap(i, j)
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+func (p normalizedStrings) Key(i int) string { return p.keys[i] }
+
+// ByStringNormalized sorts data by normalize(data.Key(i)) rather than
+// the key itself.  It's meant for Unicode normalization, so text that's
+// composed differently sorts together: pass norm.NFC.String from
+// golang.org/x/text/unicode/norm, and "é" written as one code point and
+// as "e" plus a combining accent become the same key.  Any func(string)
+// string works, though, such as strings.ToLower.
+//
+// normalize is called exactly once per item, up front, and the results
+// are kept for the length of the sort, so the cost is one normalization
+// per key plus memory for the normalized copies (normalizers generally
+// return their input unchanged when it's already normalized, making that
+// cheap for mostly-normalized text).  The original strings aren't
+// modified; only the order comes from the normalized keys.  Items whose
+// normalized keys are equal are ordered by data.Less, so the result is
+// deterministic.
+func ByStringNormalized(data StringInterface, normalize func(string) string) {
+	keys := make([]string, data.Len())
+	for i := range keys {
+		keys[i] = normalize(data.Key(i))
+	}
+	ByString(normalizedStrings{data, keys})
+}
+
+// ByBytesNormalized is ByStringNormalized for []byte keys, for
+// normalizers like norm.NFC.Bytes or norm.NFKD.Bytes.  normalize may
+// return its argument when it's already normalized, but not modify it,
+// and the slices it returns must not change during the sort.
+func ByBytesNormalized(data BytesInterface, normalize func([]byte) []byte) {
+	keys := make([][]byte, data.Len())
+	for i := range keys {
+		keys[i] = normalize(data.Key(i))
+	}
+	ByBytes(cachedBytes{data, keys})
+}
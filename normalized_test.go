@@ -0,0 +1,66 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// composeE stands in for a real NFC normalizer: it composes "e" plus a
+// combining acute accent into "é".
+func composeE(s string) string { return strings.ReplaceAll(s, "e\u0301", "\u00e9") }
+
+func TestByStringNormalized(t *testing.T) {
+	words := []string{"f", "caf\u00e9s", "e", "caf\u00e9", "cafe\u0301", "cafz"}
+	ByStringNormalized(StringSlice(words), composeE)
+	// the two spellings of "café" end up together, ordered by raw bytes
+	got := fmt.Sprintf("%+q", words)
+	if want := `["cafz" "cafe\u0301" "caf\u00e9" "caf\u00e9s" "e" "f"]`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		for i := range data {
+			data[i] = strings.ReplaceAll(data[i], "a", "\u00e9")
+		}
+		ByStringNormalized(StringSlice(data), strings.ToLower)
+		if !sort.SliceIsSorted(data, func(i, j int) bool {
+			ki, kj := strings.ToLower(data[i]), strings.ToLower(data[j])
+			if ki != kj {
+				return ki < kj
+			}
+			return data[i] < data[j]
+		}) {
+			t.Errorf("didn't sort")
+		}
+	})
+}
+
+func TestByBytesNormalized(t *testing.T) {
+	words := []string{"f", "caf\u00e9s", "e", "caf\u00e9", "cafe\u0301", "cafz"}
+	data := make(BytesSlice, len(words))
+	for i, w := range words {
+		data[i] = []byte(w)
+	}
+	ByBytesNormalized(data, func(b []byte) []byte {
+		if !bytes.Contains(b, []byte("e\u0301")) {
+			return b // already normalized
+		}
+		return []byte(composeE(string(b)))
+	})
+	got := fmt.Sprintf("%+q", data)
+	if want := `["cafz" "cafe\u0301" "caf\u00e9" "caf\u00e9s" "e" "f"]`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
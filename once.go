@@ -0,0 +1,60 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// onceKeyedUint64 serves keys collected up front, swapping them along
+// with the data.
+type onceKeyedUint64 struct {
+	Uint64Interface
+	keys []uint64
+}
+
+func (d onceKeyedUint64) Key(i int) uint64 { return d.keys[i] }
+func (d onceKeyedUint64) Swap(i, j int) {
+	d.Uint64Interface.Swap(i, j)
+	d.keys[i], d.keys[j] = d.keys[j], d.keys[i]
+}
+
+// OnceKeyedUint64 calls data.Key exactly once for each item, in index
+// order, and returns a Uint64Interface that answers Key from those saved
+// results, passing Len, Less and Swap through to data.  The radix sorts
+// call Key many times per item, in no particular order, so this wrapper
+// is required when Key has side effects or can only be called once (say,
+// it reads the next value from an iterator), and it can also help when
+// Key is expensive.  The saved keys cost 8 bytes per item.
+//
+//	sorts.ByUint64(sorts.OnceKeyedUint64(data))
+//
+// The returned value keeps the keys in the order of the last sort, so it
+// shouldn't be used after data is reordered some other way.
+func OnceKeyedUint64(data Uint64Interface) Uint64Interface {
+	keys := make([]uint64, data.Len())
+	for i := range keys {
+		keys[i] = data.Key(i)
+	}
+	return onceKeyedUint64{data, keys}
+}
+
+// onceKeyedInt64 is onceKeyedUint64 for Int64Interface.
+type onceKeyedInt64 struct {
+	Int64Interface
+	keys []int64
+}
+
+func (d onceKeyedInt64) Key(i int) int64 { return d.keys[i] }
+func (d onceKeyedInt64) Swap(i, j int) {
+	d.Int64Interface.Swap(i, j)
+	d.keys[i], d.keys[j] = d.keys[j], d.keys[i]
+}
+
+// OnceKeyedInt64 is OnceKeyedUint64 for Int64Interface.
+func OnceKeyedInt64(data Int64Interface) Int64Interface {
+	keys := make([]int64, data.Len())
+	for i := range keys {
+		keys[i] = data.Key(i)
+	}
+	return onceKeyedInt64{data, keys}
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// oneShotKeys is an IntSlice whose Key may only be called once per item.
+type oneShotKeys struct {
+	IntSlice
+	used []bool
+	t    *testing.T
+}
+
+func (p oneShotKeys) Key(i int) int64 {
+	if p.used[i] {
+		p.t.Fatalf("Key(%d) called twice", i)
+	}
+	p.used[i] = true
+	return p.IntSlice.Key(i)
+}
+
+func TestOnceKeyed(t *testing.T) {
+	varyQSortCutoff(func() {
+		n := 10000
+		data := oneShotKeys{make(IntSlice, n), make([]bool, n), t}
+		for i := range data.IntSlice {
+			data.IntSlice[i] = rand.Int()
+		}
+		ByInt64(OnceKeyedInt64(data))
+		if !IntsAreSorted(data.IntSlice) {
+			t.Errorf("OnceKeyedInt64 didn't sort")
+		}
+
+		u := make([]uint64, n)
+		for i := range u {
+			u[i] = uint64(rand.Int63())
+		}
+		ByUint64(OnceKeyedUint64(Uint64Slice(u)))
+		if !Uint64sAreSorted(u) {
+			t.Errorf("OnceKeyedUint64 didn't sort")
+		}
+	})
+}
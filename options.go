@@ -0,0 +1,191 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// radixPassBytes is the stack each level of radix recursion holds onto:
+// its bucketStarts and bucketEnds tables.
+const radixPassBytes = 2 * 256 * bits.UintSize / 8
+
+// Options adjusts how the By* methods sort.  Zero fields keep the
+// defaults, so the zero value, or a nil *Options, sorts exactly as the
+// package-level By* functions do.
+type Options struct {
+	// MaxMemoryBytes, if positive, caps the bucket tables radix sorting
+	// keeps on the stack, counted across all the goroutines sorting at
+	// once.  Each level of radix recursion holds radixPassBytes (4KB on
+	// 64-bit platforms) until its buckets are done; once going deeper
+	// would pass the budget, the range is finished by quicksort instead,
+	// which needs no tables.  Small budgets cost speed, not correctness.
+	MaxMemoryBytes int
+
+	// QSortCutoff, if nonzero, replaces the package's default of 128 as
+	// the range size below which radix sorting gives way to quicksort.
+	// Raise it for data with cheap Less and expensive Key.  Negative
+	// values are treated as 1.
+	QSortCutoff int
+
+	// MaxRadixDepth, if nonzero, replaces the default of 32 as the number
+	// of bytes into string and []byte keys the radix passes go before
+	// quicksorting what's left, for keys with long shared prefixes.  A
+	// larger value can mean more stack; MaxMemoryBytes still applies.
+	// Negative values are treated as 1.
+	MaxRadixDepth int
+
+	// RadixBits, if 16, has ByUint64 and ByInt64 bucket 16 bits of key
+	// per pass instead of 8 over ranges of at least 1<<20 items, halving
+	// the passes big inputs take; smaller ranges still get 8-bit passes.
+	// Each 16-bit pass uses 1MB of bucket tables, pooled on the heap and
+	// not counted against MaxMemoryBytes.  Whether it wins depends on the
+	// keys and the machine; on random keys in our tests it lost to 8 bits
+	// at every size, since scattering swaps over 65536 buckets misses
+	// cache far more, so measure before using it.  BenchmarkRadixBits in
+	// wide_test.go compares the two at a few sizes.  Other values mean
+	// the default, 8.
+	RadixBits int
+
+	// MaxProcs, if positive, caps the goroutines this sort uses below the
+	// package-level MaxProcs and GOMAXPROCS; 1 sorts serially.  Unlike
+	// setting the package variable, it affects only sorts run with these
+	// Options.
+	MaxProcs int
+
+	// NoVerify, if set, skips the check of the result that VerifyResults
+	// otherwise turns on, for this sort only.
+	NoVerify bool
+
+	// Stats, if non-nil, has the counts of what each sort did added to
+	// it, for seeing where a slow sort spends its passes.  Leave it nil
+	// and nothing is counted.
+	Stats *Stats
+}
+
+// settings carries the Options tunables every level of a sort reads, in
+// each of its tasks.  A nil *settings means the package defaults.
+type settings struct {
+	cutoff, maxDepth, procs int
+	wide                    bool
+}
+
+// settings returns o's tunables, or nil if o leaves them at the defaults.
+func (o *Options) settings() *settings {
+	if o == nil || o.QSortCutoff == 0 && o.MaxRadixDepth == 0 && o.MaxProcs <= 0 && o.RadixBits != wideRadix {
+		return nil
+	}
+	c := &settings{o.QSortCutoff, o.MaxRadixDepth, o.MaxProcs, o.RadixBits == wideRadix}
+	if c.cutoff < 0 {
+		c.cutoff = 1
+	}
+	if c.maxDepth < 0 {
+		c.maxDepth = 1
+	}
+	return c
+}
+
+// qSortCutoff is the cutoff for a sort using c.
+func (c *settings) qSortCutoff() int {
+	if c == nil || c.cutoff == 0 {
+		return qSortCutoff
+	}
+	return c.cutoff
+}
+
+// maxRadixDepth is the string radix depth limit for a sort using c.
+func (c *settings) maxRadixDepth() int {
+	if c == nil || c.maxDepth == 0 {
+		return maxRadixDepth
+	}
+	return c.maxDepth
+}
+
+// workers is how many goroutines a sort of l items using c may use.
+func (c *settings) workers(l int) int {
+	w := workers(l)
+	if c != nil && c.procs > 0 && c.procs < w {
+		w = c.procs
+	}
+	return w
+}
+
+// wideRadix is whether a numeric sort of l items using c should start
+// with 16-bit passes.
+func (c *settings) wideRadix(l int) bool {
+	return c != nil && c.wide && l >= wideRadixMin
+}
+
+// ByUint64 sorts data like the package-level ByUint64, within o's limits.
+func (o *Options) ByUint64(data Uint64Interface) {
+	mustSort(byUint64(data, o.runner(), o.verify(), o.settings()))
+}
+
+// ByInt64 sorts data like the package-level ByInt64, within o's limits.
+func (o *Options) ByInt64(data Int64Interface) {
+	mustSort(byInt64(data, o.runner(), o.verify(), o.settings()))
+}
+
+// ByString sorts data like the package-level ByString, within o's limits.
+func (o *Options) ByString(data StringInterface) {
+	mustSort(byString(data, o.runner(), o.verify(), o.settings()))
+}
+
+// ByBytes sorts data like the package-level ByBytes, within o's limits.
+func (o *Options) ByBytes(data BytesInterface) {
+	mustSort(byBytes(data, o.runner(), o.verify(), o.settings()))
+}
+
+// verify is whether a sort using o checks its result.
+func (o *Options) verify() bool {
+	return VerifyResults && (o == nil || !o.NoVerify)
+}
+
+// runner returns a sortRunner applying o's limits.
+func (o *Options) runner() sortRunner {
+	if o.radixLevels(0) < 0 && (o == nil || o.Stats == nil) {
+		return parallelSort
+	}
+	return func(data sort.Interface, sorter sortFunc, t task) {
+		l := data.Len()
+		sorter = o.limit(sorter, l)
+		if o.Stats != nil {
+			sorter = o.Stats.observe(sorter, data, o.radixLevels(l))
+		}
+		parallelSort(data, sorter, t)
+	}
+}
+
+// radixLevels is how many levels of radix recursion each goroutine in a
+// sort of l items may use under o, or -1 for no limit.
+func (o *Options) radixLevels(l int) int {
+	if o == nil || o.MaxMemoryBytes <= 0 {
+		return -1
+	}
+	return o.MaxMemoryBytes / (radixPassBytes * o.settings().workers(l))
+}
+
+// limit wraps sorter so tasks past o's radix depth budget for a sort of l
+// items are quicksorted instead.
+func (o *Options) limit(sorter sortFunc, l int) sortFunc {
+	levels := o.radixLevels(l)
+	if levels < 0 {
+		return sorter
+	}
+	return func(data sort.Interface, t task, sortRange func(task)) {
+		if t.offs < 0 {
+			// already quicksorting
+			quickSortWorker(data, t, sortRange)
+			return
+		}
+		if t.depth >= levels {
+			qSortPar(data, t, sortRange)
+			return
+		}
+		sorter(data, t, sortRange)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// countingUint64s counts calls to Less, which a radix sort mostly avoids
+// and a quicksort can't.
+type countingUint64s struct {
+	Uint64Slice
+	less *int64
+}
+
+func (p countingUint64s) Less(i, j int) bool {
+	atomic.AddInt64(p.less, 1)
+	return p.Uint64Slice.Less(i, j)
+}
+
+func TestMaxMemoryBytes(t *testing.T) {
+	n := 100000
+	for _, budget := range []int{0, 1, 1 << 30} {
+		o := &Options{MaxMemoryBytes: budget}
+		forceRadix(func() {
+			var less int64
+			data := countingUint64s{make(Uint64Slice, n), &less}
+			for i := range data.Uint64Slice {
+				data.Uint64Slice[i] = uint64(rand.Int63())
+			}
+			o.ByUint64(data)
+			if !Uint64sAreSorted(data.Uint64Slice) {
+				t.Errorf("budget=%d: didn't sort", budget)
+			}
+			// the check afterwards makes n-1 calls; quicksort makes many more
+			fellBack := less > int64(2*n)
+			if wantFallback := budget == 1; fellBack != wantFallback {
+				t.Errorf("budget=%d: %d calls to Less", budget, less)
+			}
+
+			strs := make([]string, n)
+			for i := range strs {
+				strs[i] = string(rune('a' + rand.Intn(26)))
+			}
+			o.ByString(StringSlice(strs))
+			if !StringsAreSorted(strs) {
+				t.Errorf("budget=%d: didn't sort strings", budget)
+			}
+		})
+	}
+}
+
+func TestOptionsTunables(t *testing.T) {
+	// 100 items is under the default cutoff, so only a lowered cutoff
+	// lets the radix sort run and spare most of the Less calls
+	for _, c := range []struct {
+		cutoff   int
+		fewCalls bool
+	}{{0, false}, {1, true}, {-5, true}, {1e9, false}} {
+		var less int64
+		data := countingUint64s{make(Uint64Slice, 100), &less}
+		for i := range data.Uint64Slice {
+			data.Uint64Slice[i] = uint64(rand.Int63())
+		}
+		(&Options{QSortCutoff: c.cutoff}).ByUint64(data)
+		if !Uint64sAreSorted(data.Uint64Slice) {
+			t.Errorf("cutoff=%d: didn't sort", c.cutoff)
+		}
+		if fewCalls := less < 200; fewCalls != c.fewCalls {
+			t.Errorf("cutoff=%d: %d calls to Less", c.cutoff, less)
+		}
+	}
+
+	// keys sharing a 64-byte prefix, past the default radix depth
+	prefix := strings.Repeat("x", 64)
+	for _, depth := range []int{0, 1, 100, -1} {
+		words := randomWords(10000)
+		for i := range words {
+			words[i] = prefix + words[i]
+		}
+		(&Options{QSortCutoff: 1, MaxRadixDepth: depth}).ByString(StringSlice(words))
+		if !StringsAreSorted(words) {
+			t.Errorf("depth=%d: didn't sort", depth)
+		}
+	}
+}
+
+// overlapUint64s records the most calls to Key that were ever in
+// progress at once.
+type overlapUint64s struct {
+	Uint64Slice
+	inFlight, most *int64
+}
+
+func (p overlapUint64s) Key(i int) uint64 {
+	n := atomic.AddInt64(p.inFlight, 1)
+	for m := atomic.LoadInt64(p.most); n > m; m = atomic.LoadInt64(p.most) {
+		if atomic.CompareAndSwapInt64(p.most, m, n) {
+			break
+		}
+	}
+	runtime.Gosched()
+	atomic.AddInt64(p.inFlight, -1)
+	return p.Uint64Slice[i]
+}
+
+// backwardUint64s has a Less that disagrees with its keys.
+type backwardUint64s struct{ Uint64Slice }
+
+func (p backwardUint64s) Less(i, j int) bool { return p.Uint64Slice[i] > p.Uint64Slice[j] }
+
+func TestOptionsProcsAndVerify(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	var inFlight, most int64
+	data := overlapUint64s{make(Uint64Slice, 50000), &inFlight, &most}
+	for i := range data.Uint64Slice {
+		data.Uint64Slice[i] = uint64(rand.Int63())
+	}
+	(&Options{MaxProcs: 1}).ByUint64(data)
+	if !Uint64sAreSorted(data.Uint64Slice) {
+		t.Errorf("MaxProcs=1: didn't sort")
+	}
+	if most != 1 {
+		t.Errorf("MaxProcs=1: %d Key calls at once", most)
+	}
+
+	rand.Shuffle(len(data.Uint64Slice), data.Swap)
+	(&Options{NoVerify: true}).ByUint64(data.Uint64Slice)
+	if !Uint64sAreSorted(data.Uint64Slice) {
+		t.Errorf("NoVerify: didn't sort")
+	}
+
+	// a Less that disagrees with Key goes unnoticed with NoVerify, and
+	// panics without it, as a check of the same data reports
+	back := backwardUint64s{make(Uint64Slice, 1000)}
+	for i := range back.Uint64Slice {
+		back.Uint64Slice[i] = uint64(rand.Intn(100))
+	}
+	orig := append(Uint64Slice(nil), back.Uint64Slice...)
+	(&Options{NoVerify: true}).ByUint64(back)
+	copy(back.Uint64Slice, orig)
+	if err, ok := ByUint64Checked(back).(*SortError); !ok || !err.KeyMismatch {
+		t.Errorf("inconsistent Less: ByUint64Checked returned %v", err)
+	}
+	copy(back.Uint64Slice, orig)
+	defer func() {
+		if recover() == nil {
+			t.Errorf("inconsistent Less didn't panic without NoVerify")
+		}
+	}()
+	(&Options{}).ByUint64(back)
+}
+
+func TestStats(t *testing.T) {
+	// a 20-byte shared prefix takes 20 skipped passes to get past
+	prefix := strings.Repeat("x", 20)
+	words := randomWords(10000)
+	for i := range words {
+		words[i] = prefix + words[i]
+	}
+	stats := new(Stats)
+	(&Options{Stats: stats}).ByString(StringSlice(words))
+	if !StringsAreSorted(words) {
+		t.Error("didn't sort")
+	}
+	if stats.PrefixSkips < 20 || stats.MaxDepth < 20 || stats.MaxDepth > 20+5 {
+		t.Errorf("20-byte prefix: %+v", *stats)
+	}
+	if stats.RadixPasses <= stats.PrefixSkips || stats.QSortFallbacks == 0 || stats.DepthFallbacks != 0 {
+		t.Errorf("20-byte prefix: %+v", *stats)
+	}
+
+	// past the radix depth limit, the rest is quicksorted
+	*stats = Stats{}
+	(&Options{Stats: stats, MaxRadixDepth: 10}).ByString(StringSlice(words))
+	if stats.RadixPasses != 10 || stats.DepthFallbacks != 1 || stats.MaxDepth != 9 {
+		t.Errorf("depth limit 10: %+v", *stats)
+	}
+
+	*stats = Stats{}
+	data := make(Uint64Slice, 10000)
+	for i := range data {
+		data[i] = uint64(rand.Int63())
+	}
+	(&Options{Stats: stats}).ByUint64(data)
+	if stats.RadixPasses == 0 || stats.MaxDepth > 8 {
+		t.Errorf("uint64s: %+v", *stats)
+	}
+}
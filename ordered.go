@@ -0,0 +1,455 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// byteOrder describes a nonstandard ordering of string or []byte keys:
+// the byte at each offset below depth is bucketed and compared by rank[b]
+// instead of by b itself, and bytes from depth on compare as usual.  If
+// shortLast is set, a key that's a prefix of another sorts after it
+// instead of before.
+type byteOrder struct {
+	rank      [256]byte
+	depth     int
+	shortLast bool
+}
+
+// compareStrings compares a and b under o, returning -1, 0, or 1.
+func (o *byteOrder) compareStrings(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := a[i], b[i]
+		if i < o.depth {
+			ca, cb = o.rank[ca], o.rank[cb]
+		}
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return o.compareLengths(len(a), len(b))
+}
+
+// compareLengths orders keys with one a prefix of the other, by lengths
+// la and lb.
+func (o *byteOrder) compareLengths(la, lb int) int {
+	c := 0
+	switch {
+	case la < lb:
+		c = -1
+	case la > lb:
+		c = 1
+	}
+	if o.shortLast {
+		c = -c
+	}
+	return c
+}
+
+// compareBytes is compareStrings for []byte keys.
+func (o *byteOrder) compareBytes(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := a[i], b[i]
+		if i < o.depth {
+			ca, cb = o.rank[ca], o.rank[cb]
+		}
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+			return 1
+		}
+	}
+	return o.compareLengths(len(a), len(b))
+}
+
+// orderedStrings replaces a StringInterface's Less with a comparison of
+// keys under a byteOrder, so the quicksort fallbacks agree with the radix
+// passes.
+type orderedStrings struct {
+	StringInterface
+	order *byteOrder
+}
+
+func (d orderedStrings) Less(i, j int) bool {
+	return d.order.compareStrings(d.Key(i), d.Key(j)) < 0
+}
+
+// orderedBytes is orderedStrings for BytesInterface.
+type orderedBytes struct {
+	BytesInterface
+	order *byteOrder
+}
+
+func (d orderedBytes) Less(i, j int) bool {
+	return d.order.compareBytes(d.Key(i), d.Key(j)) < 0
+}
+
+// radixSortString is radixSortString with bytes at offsets below o.depth
+// bucketed through o.rank.
+func (o *byteOrder) radixSortString(dataI sort.Interface, t task, sortRange func(task)) {
+	data := dataI.(StringInterface)
+	offset, a, b := t.offs, t.pos, t.end
+	if offset < 0 || offset >= o.depth {
+		radixSortString(dataI, t, sortRange)
+		return
+	}
+	if b-a < t.cfg.qSortCutoff() {
+		qSort(data, a, b)
+		return
+	}
+	if offset >= t.cfg.maxRadixDepth() {
+		qSortPar(data, t, sortRange)
+		return
+	}
+
+	// swap too-short strings to start (or end) and count bucket sizes
+	bucketStarts, bucketEnds := [256]int{}, [256]int{}
+	aInitial, bInitial := a, b
+	for i := a; i < b; {
+		k := data.Key(i)
+		if len(k) <= offset {
+			if o.shortLast {
+				b--
+				data.Swap(i, b)
+				continue
+			}
+			data.Swap(a, i)
+			a++
+			i++
+			continue
+		}
+		bucketStarts[o.rank[k[offset]]]++
+		i++
+	}
+	if a > aInitial+1 {
+		qSortEqualKeyRange(data, aInitial, a)
+	}
+	if bInitial > b+1 {
+		qSortEqualKeyRange(data, b, bInitial)
+	}
+
+	pos := a
+	for i, c := range bucketStarts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+		if bucketStarts[i] == a && bucketEnds[i] == b {
+			// everything was in the same bucket
+			sortRange(task{offset + 1, a, b, t.depth + 1, t.cfg})
+			return
+		}
+	}
+
+	i := a
+	for curBucket, bucketEnd := range bucketEnds {
+		start := i
+		i = bucketStarts[curBucket]
+		for i < bucketEnd {
+			destBucket := o.rank[data.Key(i)[offset]]
+			if destBucket == byte(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			data.Swap(i, bucketStarts[destBucket])
+			bucketStarts[destBucket]++
+		}
+		if i > start+1 {
+			sortRange(task{offset + 1, start, i, t.depth + 1, t.cfg})
+		}
+	}
+}
+
+// radixSortBytes is radixSortBytes with bytes at offsets below o.depth
+// bucketed through o.rank.
+func (o *byteOrder) radixSortBytes(dataI sort.Interface, t task, sortRange func(task)) {
+	data := dataI.(BytesInterface)
+	offset, a, b := t.offs, t.pos, t.end
+	if offset < 0 || offset >= o.depth {
+		radixSortBytes(dataI, t, sortRange)
+		return
+	}
+	if b-a < t.cfg.qSortCutoff() {
+		qSort(data, a, b)
+		return
+	}
+	if offset >= t.cfg.maxRadixDepth() {
+		qSortPar(data, t, sortRange)
+		return
+	}
+
+	// swap too-short strings to start (or end) and count bucket sizes
+	bucketStarts, bucketEnds := [256]int{}, [256]int{}
+	aInitial, bInitial := a, b
+	for i := a; i < b; {
+		k := data.Key(i)
+		if len(k) <= offset {
+			if o.shortLast {
+				b--
+				data.Swap(i, b)
+				continue
+			}
+			data.Swap(a, i)
+			a++
+			i++
+			continue
+		}
+		bucketStarts[o.rank[k[offset]]]++
+		i++
+	}
+	if a > aInitial+1 {
+		qSortEqualKeyRange(data, aInitial, a)
+	}
+	if bInitial > b+1 {
+		qSortEqualKeyRange(data, b, bInitial)
+	}
+
+	pos := a
+	for i, c := range bucketStarts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+		if bucketStarts[i] == a && bucketEnds[i] == b {
+			// everything was in the same bucket
+			sortRange(task{offset + 1, a, b, t.depth + 1, t.cfg})
+			return
+		}
+	}
+
+	i := a
+	for curBucket, bucketEnd := range bucketEnds {
+		start := i
+		i = bucketStarts[curBucket]
+		for i < bucketEnd {
+			destBucket := o.rank[data.Key(i)[offset]]
+			if destBucket == byte(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			data.Swap(i, bucketStarts[destBucket])
+			bucketStarts[destBucket]++
+		}
+		if i > start+1 {
+			sortRange(task{offset + 1, start, i, t.depth + 1, t.cfg})
+		}
+	}
+}
+
+// byStringOrdered sorts data by its string keys under o.  data.Less is
+// never called.
+func byStringOrdered(data StringInterface, o *byteOrder) {
+	od := orderedStrings{data, o}
+	l := od.Len()
+	if l < qSortCutoff {
+		qSort(od, 0, l)
+		return
+	}
+
+	parallelSort(od, o.radixSortString, task{end: l})
+
+	if !VerifyResults {
+		return
+	}
+	// check results if we radix sorted!
+	for i := 1; i < l; i++ {
+		if od.Less(i, i-1) {
+			panic(panicMessage)
+		}
+	}
+}
+
+// byBytesOrdered sorts data by its []byte keys under o.  data.Less is
+// never called.
+func byBytesOrdered(data BytesInterface, o *byteOrder) {
+	od := orderedBytes{data, o}
+	l := od.Len()
+	if l < qSortCutoff {
+		qSort(od, 0, l)
+		return
+	}
+
+	parallelSort(od, o.radixSortBytes, task{end: l})
+
+	if !VerifyResults {
+		return
+	}
+	// check results if we radix sorted!
+	for i := 1; i < l; i++ {
+		if od.Less(i, i-1) {
+			panic(panicMessage)
+		}
+	}
+}
+
+// allBytes is a byteOrder depth covering every offset.
+const allBytes = int(^uint(0) >> 1)
+
+// asciiFold maps A-Z to a-z and leaves other bytes alone.
+func asciiFold(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// foldFirstOrder ranks first bytes by their ASCII-folded value, then by
+// their raw value, so 'A' and 'a' are adjacent and 'A' comes first.
+var foldFirstOrder = func() *byteOrder {
+	o := &byteOrder{depth: 1}
+	r := 0
+	for c := 0; c < 256; c++ {
+		if asciiFold(byte(c)) != byte(c) { // uppercase: ranked by its lowercase
+			continue
+		}
+		if 'a' <= c && c <= 'z' {
+			o.rank[c-'a'+'A'] = byte(r)
+			r++
+		}
+		o.rank[c] = byte(r)
+		r++
+	}
+	return o
+}()
+
+// FoldFirstLess reports whether a sorts before b in the order
+// ByStringFoldFirst produces.
+func FoldFirstLess(a, b string) bool {
+	return foldFirstOrder.compareStrings(a, b) < 0
+}
+
+// ByStringFoldFirst sorts data by string key, ASCII case-folding only the
+// first byte.  Keys are grouped by their first letter regardless of case,
+// and each group is sorted by the exact, case-sensitive key, so uppercase
+// keys still precede lowercase ones within a group:
+//
+//	Apple Apricot apple banana Berry
+//
+// becomes
+//
+//	Apple Apricot apple Berry banana
+//
+// Uppercase first letters sort where their lowercase forms would, so "_x"
+// precedes "Apple".  This keeps keys differing only in case near each
+// other for about the cost of ByString, without building folded copies of
+// the keys.  It is not a case-insensitive sort: only the first byte is
+// folded.  The order comes from the keys alone (FoldFirstLess reproduces
+// it); data.Less is not called.
+func ByStringFoldFirst(data StringInterface) {
+	byStringOrdered(data, foldFirstOrder)
+}
+
+// FoldKey maps each byte to its ASCII-folded value, A-Z to a-z and
+// everything else to itself: the ranks ByStringFold and ByBytesFold
+// bucket bytes by.  Pass it, or a modified copy, to NewByteOrder to build
+// an order on top of case folding.  Changing it doesn't change
+// ByStringFold.
+var FoldKey = func() (rank [256]byte) {
+	for c := range rank {
+		rank[c] = asciiFold(byte(c))
+	}
+	return rank
+}()
+
+// foldOrder ranks every byte by FoldKey.
+var foldOrder = &byteOrder{rank: FoldKey, depth: allBytes}
+
+// FoldLess reports whether a sorts before b ignoring ASCII case, the order
+// ByStringFold produces.
+func FoldLess(a, b string) bool {
+	return foldOrder.compareStrings(a, b) < 0
+}
+
+// ByStringFold sorts data by string key, ignoring ASCII case: A-Z are
+// treated as a-z at every byte, in the radix passes and the quicksort
+// fallbacks alike, so "apple" and "Apple" sort together without lowercased
+// copies of the keys being made.  Only ASCII letters are folded; other
+// bytes, including those of non-ASCII UTF-8 letters, are compared as is.
+// Keys differing only in case may end up in either order.  The order
+// comes from the keys alone (FoldLess reproduces it); data.Less is not
+// called.
+func ByStringFold(data StringInterface) {
+	byStringOrdered(data, foldOrder)
+}
+
+// CompareBytesFold compares a and b like bytes.Compare, but ignoring ASCII
+// case, the order ByBytesFold produces.
+func CompareBytesFold(a, b []byte) int {
+	return foldOrder.compareBytes(a, b)
+}
+
+// ByBytesFold is ByStringFold for []byte keys, folding A-Z to a-z as each
+// byte is bucketed.
+func ByBytesFold(data BytesInterface) {
+	byBytesOrdered(data, foldOrder)
+}
+
+// signedOrder ranks bytes as the int8s they'd be in two's complement.
+var signedOrder = func() *byteOrder {
+	o := &byteOrder{depth: allBytes}
+	for c := range o.rank {
+		o.rank[c] = byte(c) ^ 0x80
+	}
+	return o
+}()
+
+// CompareBytesSigned compares a and b like bytes.Compare, but treating
+// each byte as a signed int8, so 0x80 through 0xFF (-128 through -1) sort
+// before 0x00 through 0x7F.
+func CompareBytesSigned(a, b []byte) int {
+	return signedOrder.compareBytes(a, b)
+}
+
+// ByBytesSigned sorts data by []byte key, comparing each byte as a signed
+// int8 the way CompareBytesSigned does, for legacy formats that order
+// keys that way.  Like ByBytes it's a radix sort: each byte is just
+// bucketed through a table putting 0x80-0xFF ahead of 0x00-0x7F.  The
+// order comes from the keys alone; data.Less is not called.
+func ByBytesSigned(data BytesInterface) {
+	byBytesOrdered(data, signedOrder)
+}
+
+// descendingOrder reverses the usual byte order at every offset, with
+// shorter keys after the longer keys they're prefixes of.
+var descendingOrder = func() *byteOrder {
+	o := &byteOrder{depth: allBytes, shortLast: true}
+	for c := range o.rank {
+		o.rank[c] = ^byte(c)
+	}
+	return o
+}()
+
+// A ByteOrder is a caller-defined order for the bytes of string or
+// []byte keys, such as putting digits after letters, following a legacy
+// encoding's collating sequence, or ignoring case.  It sorts as
+// ByStringFold does: each byte is bucketed, and compared, through the
+// rank table, so it costs about what ByString does and makes no copies
+// of the keys.  A ByteOrder is safe for concurrent use.
+type ByteOrder struct{ o byteOrder }
+
+// NewByteOrder returns the ByteOrder that sorts byte b by rank[b], at
+// every offset, with a key that's a prefix of another sorting first.
+// rank needn't be a permutation: bytes with equal ranks compare equal,
+// and keys equal under the ranks end up in no particular order.
+func NewByteOrder(rank [256]byte) *ByteOrder {
+	return &ByteOrder{byteOrder{rank: rank, depth: allBytes}}
+}
+
+// Less reports whether a sorts before b under o.
+func (o *ByteOrder) Less(a, b string) bool { return o.o.compareStrings(a, b) < 0 }
+
+// Compare compares a and b under o, like bytes.Compare.
+func (o *ByteOrder) Compare(a, b []byte) int { return o.o.compareBytes(a, b) }
+
+// ByString sorts data by string key under o.  The order comes from the
+// keys alone (o.Less reproduces it); data.Less is not called.
+func (o *ByteOrder) ByString(data StringInterface) { byStringOrdered(data, &o.o) }
+
+// ByBytes is ByString for []byte keys; o.Compare reproduces its order.
+func (o *ByteOrder) ByBytes(data BytesInterface) { byBytesOrdered(data, &o.o) }
@@ -0,0 +1,150 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// randomWords makes n short strings from a small alphabet of mixed-case
+// letters, so there are lots of shared prefixes and case-only differences.
+func randomWords(n int) []string {
+	const letters = "aAbBcCzZ_0"
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, rand.Intn(6))
+		for j := range b {
+			b[j] = letters[rand.Intn(len(letters))]
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+func TestByStringFoldFirst(t *testing.T) {
+	words := []string{"apple", "Berry", "Apricot", "banana", "Apple", "", "_x", "a"}
+	ByStringFoldFirst(StringSlice(words))
+	got := fmt.Sprint(words)
+	if want := "[ _x Apple Apricot a apple Berry banana]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		ByStringFoldFirst(StringSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return FoldFirstLess(data[i], data[j]) }) {
+			t.Errorf("ByStringFoldFirst didn't sort")
+		}
+	})
+}
+
+func TestByBytesSigned(t *testing.T) {
+	keys := [][]byte{{0x7f}, {0x80}, {0x00}, {0xff}, {}, {0x01, 0x80}, {0x01, 0x00}}
+	signed := append([][]byte(nil), keys...)
+	ByBytesSigned(BytesSlice(signed))
+	if got, want := fmt.Sprint(signed), "[[] [128] [255] [0] [1 128] [1 0] [127]]"; got != want {
+		t.Errorf("signed: got %s, want %s", got, want)
+	}
+	unsigned := append([][]byte(nil), keys...)
+	ByBytes(BytesSlice(unsigned))
+	if got, want := fmt.Sprint(unsigned), "[[] [0] [1 0] [1 128] [127] [128] [255]]"; got != want {
+		t.Errorf("unsigned: got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := make([][]byte, 10000)
+		for i := range data {
+			data[i] = make([]byte, rand.Intn(4))
+			for j := range data[i] {
+				data[i][j] = byte(rand.Intn(4)) + 0x7e // mix of both signs
+			}
+		}
+		ByBytesSigned(BytesSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return CompareBytesSigned(data[i], data[j]) < 0 }) {
+			t.Errorf("ByBytesSigned didn't sort")
+		}
+	})
+}
+
+func TestByStringFold(t *testing.T) {
+	words := []string{"banana", "Apple", "abc", "ABD", "apple", "_"}
+	ByStringFold(StringSlice(words))
+	folded := fmt.Sprint(words)
+	if folded != "[_ abc ABD Apple apple banana]" && folded != "[_ abc ABD apple Apple banana]" {
+		t.Errorf("got %s", folded)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		ByStringFold(StringSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return FoldLess(data[i], data[j]) }) {
+			t.Errorf("ByStringFold didn't sort")
+		}
+
+		keys := make(BytesSlice, len(data))
+		for i, w := range randomWords(len(keys)) {
+			keys[i] = []byte(w)
+		}
+		ByBytesFold(keys)
+		if !sort.SliceIsSorted(keys, func(i, j int) bool { return CompareBytesFold(keys[i], keys[j]) < 0 }) {
+			t.Errorf("ByBytesFold didn't sort")
+		}
+
+		rand.Shuffle(len(data), StringSlice(data).Swap)
+		NewByteOrder(FoldKey).ByString(StringSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return FoldLess(data[i], data[j]) }) {
+			t.Errorf("NewByteOrder(FoldKey) didn't sort as FoldLess does")
+		}
+	})
+}
+
+func TestByteOrder(t *testing.T) {
+	// letters, then digits, then everything else
+	var rank [256]byte
+	var ranked [256]bool
+	next := 0
+	for _, class := range []func(c byte) bool{
+		func(c byte) bool { return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' },
+		func(c byte) bool { return '0' <= c && c <= '9' },
+		func(c byte) bool { return true },
+	} {
+		for c := 0; c < 256; c++ {
+			if !ranked[c] && class(byte(c)) {
+				rank[c], ranked[c] = byte(next), true
+				next++
+			}
+		}
+	}
+	o := NewByteOrder(rank)
+
+	words := []string{"_a", "a1", "ab", "0", "a", "Z", "a_"}
+	o.ByString(StringSlice(words))
+	if got, want := fmt.Sprint(words), "[Z a ab a1 a_ 0 _a]"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	varyQSortCutoff(func() {
+		data := randomWords(10000)
+		o.ByString(StringSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return o.Less(data[i], data[j]) }) {
+			t.Errorf("ByString didn't sort")
+		}
+		keys := make(BytesSlice, len(data))
+		for i, w := range randomWords(len(keys)) {
+			keys[i] = []byte(w)
+		}
+		o.ByBytes(keys)
+		if !sort.SliceIsSorted(keys, func(i, j int) bool { return o.Compare(keys[i], keys[j]) < 0 }) {
+			t.Errorf("ByBytes didn't sort")
+		}
+	})
+}
@@ -0,0 +1,32 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+const pairsLengthMessage = "SortPairs: keys and vals have different lengths"
+
+// intPairs is an intSlice of keys that carries a parallel slice of values
+// along when it swaps.
+type intPairs[K Integer, V any] struct {
+	intSlice[K]
+	vals []V
+}
+
+func (p intPairs[K, V]) Swap(i, j int) {
+	p.a[i], p.a[j] = p.a[j], p.a[i]
+	p.vals[i], p.vals[j] = p.vals[j], p.vals[i]
+}
+
+// SortPairs sorts keys in increasing order, moving each element of vals
+// along with its key, for the common pair of parallel slices that would
+// otherwise need its own Uint64Interface type.  Elements with equal keys
+// end up in no particular order.  It panics if keys and vals have
+// different lengths.
+func SortPairs[K Integer, V any](keys []K, vals []V) {
+	if len(keys) != len(vals) {
+		panic(pairsLengthMessage)
+	}
+	ByUint64(intPairs[K, V]{newIntSlice(keys), vals})
+}
@@ -0,0 +1,46 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+func TestSortPairs(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 100000} {
+		keys := make([]int32, n)
+		vals := make([]string, n)
+		for i := range keys {
+			keys[i] = rand.Int31n(1000) - 500
+			vals[i] = string(rune(keys[i] + 1000)) // so we can tell pairs stayed together
+		}
+		varyQSortCutoff(func() {
+			rand.Shuffle(n, func(i, j int) {
+				keys[i], keys[j] = keys[j], keys[i]
+				vals[i], vals[j] = vals[j], vals[i]
+			})
+			SortPairs(keys, vals)
+			for i := range keys {
+				if i > 0 && keys[i] < keys[i-1] {
+					t.Fatalf("n=%d: keys not sorted at %d", n, i)
+				}
+				if vals[i] != string(rune(keys[i]+1000)) {
+					t.Fatalf("n=%d: pair %d is (%d, %q)", n, i, keys[i], vals[i])
+				}
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for mismatched lengths")
+		}
+	}()
+	SortPairs([]uint64{1, 2}, []int{1})
+}
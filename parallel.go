@@ -10,12 +10,16 @@ import (
 
 type sortFunc func(sort.Interface, task, func(task))
 
+// sortRunner runs a sort starting from one task, like parallelSort.
+type sortRunner func(data sort.Interface, sorter sortFunc, initialTask task)
+
 // MaxProcs controls how many goroutines to start for large sorts. If 0,
 // GOMAXPROCS will be used; if 1, all sorts will be serial.
 var MaxProcs = 0
 
-// minParallel is the size of the smallest collection we will try to sort in
-// parallel.
+// minParallel is how many items each worker goroutine gets; workers
+// starts one per minParallel items, so nothing under 2*minParallel items
+// is sorted in parallel.
 var minParallel = 10000
 
 // minOffload is the size of the smallest range that can be offloaded to
@@ -26,17 +30,33 @@ var minOffload = 127
 // worker goroutine.
 var bufferRatio float32 = 1
 
-// parallelSort calls the sorters with an asyncSort function that will hand
-// the task off to another goroutine when possible.
-func parallelSort(data sort.Interface, sorter sortFunc, initialTask task) {
+// workers is how many goroutines parallelSort will use for l items: one
+// per minParallel items, up to GOMAXPROCS or MaxProcs, so a sort just big
+// enough to go parallel doesn't start a goroutine per P with too little
+// work for each to be worth starting.
+func workers(l int) int {
 	max := runtime.GOMAXPROCS(0)
 	if MaxProcs > 0 && MaxProcs < max {
 		max = MaxProcs
 	}
-	l := data.Len()
-	if l < minParallel {
+	if n := l / minParallel; n < max {
+		max = n
+	}
+	if max < 1 {
 		max = 1
 	}
+	return max
+}
+
+// parallelSort calls the sorters with an asyncSort function that will hand
+// the task off to another goroutine when possible.  The goroutines share
+// one queue rather than each having its own, and any of them offers every
+// range it splits off there, so a single huge bucket in skewed data is
+// split up among idle workers as it's sorted; when the queue is full, the
+// range is sorted in place by whoever split it off, which keeps the
+// queue short without anyone waiting on it.
+func parallelSort(data sort.Interface, sorter sortFunc, initialTask task) {
+	max := initialTask.cfg.workers(data.Len())
 
 	var syncSort func(t task)
 	syncSort = func(t task) {
@@ -79,3 +99,147 @@ func parallelSort(data sort.Interface, sorter sortFunc, initialTask task) {
 	wg.Wait()
 	close(sorts)
 }
+
+// minParallelCount is the size of the smallest range whose counting pass
+// is split across goroutines.
+var minParallelCount = 1 << 17
+
+// countStripes is how many stripes to split the counting pass over t's
+// range into, for a sort of l items.  Only the sort's first range, or a
+// re-pass over that same range, is counted in stripes: any later range
+// is already sorted by one of parallelSort's workers while the others
+// sort theirs, and more goroutines on top of them would go past
+// MaxProcs.
+func countStripes(t task, l int) int {
+	if t.end-t.pos < minParallelCount || t.depth > 0 && (t.pos > 0 || t.end < l) {
+		return 1
+	}
+	return t.cfg.workers(t.end - t.pos)
+}
+
+// parallelCount is the counting pass of radixSortUint64 and
+// radixSortInt64 for items a through b-1, split into w stripes that are
+// counted at once, each into its own table, then summed into counts.  It
+// returns the smallest and largest keys.  Key is called concurrently, as
+// it already is on separate buckets during a parallel sort.
+func parallelCount(data Uint64Interface, shift uint, a, b, w int, counts *[1 << radix]int) (min, max uint64) {
+	type stripe struct {
+		counts   [1 << radix]int
+		min, max uint64
+	}
+	stripes := make([]stripe, w)
+	inStripes(a, b, w, func(s, lo, hi int) {
+		st := &stripes[s]
+		st.min = data.Key(lo)
+		st.max = st.min
+		for i := lo; i < hi; i++ {
+			k := data.Key(i)
+			st.counts[(k>>shift)&mask]++
+			if k < st.min {
+				st.min = k
+			}
+			if k > st.max {
+				st.max = k
+			}
+		}
+	})
+
+	min, max = stripes[0].min, stripes[0].max
+	for s := range stripes {
+		st := &stripes[s]
+		for i, c := range st.counts {
+			counts[i] += c
+		}
+		if st.min < min {
+			min = st.min
+		}
+		if st.max > max {
+			max = st.max
+		}
+	}
+	return min, max
+}
+
+// parallelCountKeys is the counting pass of radixSortString and
+// radixSortBytes for items a through b-1, by the byte at offset, split
+// into w stripes like parallelCount's.  It reports whether it filled in
+// counts, which it doesn't if any key is too short to have a byte at
+// offset: those need swapping to the start, which the serial pass does.
+func parallelCountKeys[T text](data interface{ Key(int) T }, offset, a, b, w int, counts *[1 << radix]int) bool {
+	type stripe struct {
+		counts [1 << radix]int
+		short  bool
+	}
+	stripes := make([]stripe, w)
+	inStripes(a, b, w, func(s, lo, hi int) {
+		st := &stripes[s]
+		for i := lo; i < hi; i++ {
+			k := data.Key(i)
+			if len(k) <= offset {
+				st.short = true
+				return
+			}
+			st.counts[k[offset]]++
+		}
+	})
+
+	for s := range stripes {
+		if stripes[s].short {
+			return false
+		}
+	}
+	for s := range stripes {
+		for i, c := range stripes[s].counts {
+			counts[i] += c
+		}
+	}
+	return true
+}
+
+// firstUnsorted returns the first i for which data.Less(i, i-1), or 0
+// if the first l items are sorted.  With w workers, it checks w stripes
+// at once, each also comparing its first item with the last item of the
+// stripe before.
+func firstUnsorted(data sort.Interface, l, w int) int {
+	if w <= 1 || l < minParallelCount {
+		for i := 1; i < l; i++ {
+			if data.Less(i, i-1) {
+				return i
+			}
+		}
+		return 0
+	}
+	firsts := make([]int, w)
+	inStripes(0, l, w, func(s, lo, hi int) {
+		if lo == 0 {
+			lo = 1
+		}
+		for i := lo; i < hi; i++ {
+			if data.Less(i, i-1) {
+				firsts[s] = i
+				return
+			}
+		}
+	})
+	for _, i := range firsts {
+		if i > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// inStripes splits items a through b-1 into w contiguous stripes and
+// calls f on each at once, in its own goroutine, returning when they're
+// all done.  Stripe s runs from lo to hi-1.
+func inStripes(a, b, w int, f func(s, lo, hi int)) {
+	wg := new(sync.WaitGroup)
+	wg.Add(w)
+	for s := 0; s < w; s++ {
+		go func(s int) {
+			f(s, a+(b-a)*s/w, a+(b-a)*(s+1)/w)
+			wg.Done()
+		}(s)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// TestParallelSorts runs big sorts with several goroutines, even on a
+// one-CPU machine, so `go test -race` can check that goroutines only
+// touch their own buckets.
+func TestParallelSorts(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	defer func(m int) { MaxProcs = m }(MaxProcs)
+	MaxProcs = 0
+	n := 200000
+	if testing.Short() {
+		n = 20000
+	}
+
+	u := make([]uint64, n)
+	for i := range u {
+		u[i] = uint64(rand.Int63())
+	}
+	ByUint64(Uint64Slice(u))
+	if !Uint64sAreSorted(u) {
+		t.Errorf("ByUint64 didn't sort")
+	}
+
+	// lower minParallelCount so the first counting pass is split across
+	// goroutines even with -short
+	defer SetMinParallelCount(SetMinParallelCount(1000))
+	i := make([]int64, n)
+	for j := range i {
+		i[j] = rand.Int63() - rand.Int63()
+		u[j] = uint64(rand.Int63())
+	}
+	ByInt64(Int64Slice(i))
+	if !Int64sAreSorted(i) {
+		t.Errorf("ByInt64 with parallel counting didn't sort")
+	}
+	ByUint64(Uint64Slice(u))
+	if !Uint64sAreSorted(u) {
+		t.Errorf("ByUint64 with parallel counting didn't sort")
+	}
+
+	s := make([]string, n)
+	for i := range s {
+		s[i] = randomWords(1)[0] + randomWords(1)[0]
+	}
+	ByString(StringSlice(s))
+	if !StringsAreSorted(s) {
+		t.Errorf("ByString didn't sort")
+	}
+
+	b := make([][]byte, n)
+	for i := range b {
+		b[i] = []byte(s[rand.Intn(n)])
+	}
+	ByBytes(BytesSlice(b))
+	if !BytesAreSorted(b) {
+		t.Errorf("ByBytes didn't sort")
+	}
+
+	// count the first pass's bytes in stripes again, now with keys too
+	// short to count, which sends it back to the serial pass
+	rand.Shuffle(n, StringSlice(s).Swap)
+	s[0], s[1] = "", ""
+	ByString(StringSlice(s))
+	if !StringsAreSorted(s) {
+		t.Errorf("ByString with parallel counting didn't sort")
+	}
+	rand.Shuffle(n, BytesSlice(b).Swap)
+	ByBytes(BytesSlice(b))
+	if !BytesAreSorted(b) {
+		t.Errorf("ByBytes with parallel counting didn't sort")
+	}
+
+	rand.Shuffle(n, StringSlice(s).Swap)
+	SortStrings(s)
+	if !StringsAreSorted(s) {
+		t.Errorf("SortStrings didn't sort")
+	}
+	rand.Shuffle(n, BytesSlice(b).Swap)
+	SortByteSlices(b)
+	if !BytesAreSorted(b) {
+		t.Errorf("SortByteSlices didn't sort")
+	}
+
+	Quicksort(Uint64Slice(u[:n/2]))
+	if !Uint64sAreSorted(u[:n/2]) {
+		t.Errorf("Quicksort didn't sort")
+	}
+}
+
+// TestParallelCheck has the check after a sort find the first item out
+// of order even when it checks stripes in parallel.
+func TestParallelCheck(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	defer SetMinParallelCount(SetMinParallelCount(1000))
+	for _, n := range []int{50000, 100000} {
+		// Less disagrees with Key, so every quicksorted range is backward
+		back := backwardUint64s{make(Uint64Slice, n)}
+		for i := range back.Uint64Slice {
+			back.Uint64Slice[i] = uint64(rand.Intn(n)) << 32
+		}
+		err, ok := ByUint64Checked(back).(*SortError)
+		if !ok {
+			t.Fatalf("n=%d: no *SortError for a backward Less", n)
+		}
+		want := 0
+		for i := 1; i < n && want == 0; i++ {
+			if back.Less(i, i-1) {
+				want = i
+			}
+		}
+		if err.Index != want || !err.KeyMismatch {
+			t.Errorf("n=%d: error at %d (key mismatch %v), want %d", n, err.Index, err.KeyMismatch, want)
+		}
+	}
+}
+
+func TestWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	defer func(m int) { MaxProcs = m }(MaxProcs)
+	for _, c := range []struct{ maxProcs, l, want int }{
+		{0, 0, 1},
+		{0, 9999, 1},
+		{0, 10000, 1},
+		{0, 25000, 2},
+		{0, 1e6, 8},
+		{3, 1e6, 3},
+		{1, 1e6, 1},
+	} {
+		MaxProcs = c.maxProcs
+		if got := Workers(c.l); got != c.want {
+			t.Errorf("MaxProcs=%d, %d items: %d workers, want %d", c.maxProcs, c.l, got, c.want)
+		}
+	}
+}
+
+// TestParallelSkewed sorts keys nearly all in one top-level bucket, so
+// the other workers only have work once that bucket is split.
+func TestParallelSkewed(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 100000
+	u := make([]uint64, n)
+	for i := range u {
+		u[i] = uint64(rand.Int63()) >> 8
+		if i%20 == 0 {
+			u[i] |= uint64(rand.Intn(256)) << 56
+		}
+	}
+	ByUint64(Uint64Slice(u))
+	if !Uint64sAreSorted(u) {
+		t.Errorf("ByUint64 didn't sort skewed keys")
+	}
+}
+
+func benchProcs(b *testing.B, procs int) {
+	defer func(m int) { MaxProcs = m }(MaxProcs)
+	MaxProcs = procs
+	b.StopTimer()
+	data := make([]uint64, 1<<22)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = uint64(rand.Int63())
+		}
+		b.StartTimer()
+		ByUint64(Uint64Slice(data))
+		b.StopTimer()
+	}
+}
+
+// Compare these on a multicore machine to see the parallel speedup.
+func BenchmarkByUint64Serial(b *testing.B)   { benchProcs(b, 1) }
+func BenchmarkByUint64Parallel(b *testing.B) { benchProcs(b, 0) }
@@ -0,0 +1,86 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByStringPrefixed(t *testing.T) {
+	// short words, with some padded past 8 bytes to tie on prefixes
+	varyQSortCutoff(func() {
+		words := randomWords(10000)
+		for i := range words {
+			if rand.Intn(2) == 0 {
+				words[i] = "prefix__" + words[i]
+			}
+		}
+		words[0], words[1], words[2] = "ab", "ab\x00", ""
+		ByStringPrefixed(StringSlice(words))
+		if !sort.StringsAreSorted(words) {
+			t.Errorf("didn't sort")
+		}
+	})
+}
+
+// longWords are 1<<16 random 64-byte strings with diverse prefixes.
+func longWords() []string {
+	words := make([]string, 1<<16)
+	b := make([]byte, 64)
+	for i := range words {
+		rand.Read(b)
+		words[i] = string(b)
+	}
+	return words
+}
+
+func benchPrefixed(b *testing.B, sorter func(StringInterface)) {
+	b.StopTimer()
+	words := longWords()
+	for i := 0; i < b.N; i++ {
+		rand.Shuffle(len(words), StringSlice(words).Swap)
+		b.StartTimer()
+		sorter(StringSlice(words))
+		b.StopTimer()
+	}
+}
+
+func BenchmarkByStringPrefixed(b *testing.B) { benchPrefixed(b, ByStringPrefixed) }
+
+func BenchmarkByStringLong(b *testing.B) { benchPrefixed(b, ByString) }
+
+// copiedStrings has a costly Key: it converts each []byte to a string,
+// copying it, on every call.
+type copiedStrings [][]byte
+
+func (p copiedStrings) Len() int           { return len(p) }
+func (p copiedStrings) Less(i, j int) bool { return p.Key(i) < p.Key(j) }
+func (p copiedStrings) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p copiedStrings) Key(i int) string   { return string(p[i]) }
+
+func benchCopied(b *testing.B, sorter func(StringInterface)) {
+	b.StopTimer()
+	words := longWords()
+	data := make(copiedStrings, len(words))
+	for i := 0; i < b.N; i++ {
+		rand.Shuffle(len(words), StringSlice(words).Swap)
+		for i, w := range words {
+			data[i] = []byte(w)
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkByStringPrefixedCostlyKey(b *testing.B) { benchCopied(b, ByStringPrefixed) }
+
+func BenchmarkByStringCostlyKey(b *testing.B) { benchCopied(b, ByString) }
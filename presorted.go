@@ -0,0 +1,61 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// sortPresorted's insertion sort gives up after one swap per
+// presortBudget items.  That's stingy because swaps it makes before
+// giving up are wasted, and a radix sort only costs a few per item.
+const presortBudget = 8
+
+// presortSamples is how many evenly spaced places samplesOrdered checks.
+// Tests set it to 0 to turn the check off.
+var presortSamples = 64
+
+// samplesOrdered checks whether the keys at presortSamples evenly spaced
+// places in data, and the keys just before each of them, are all in
+// ascending or all in descending order.  Equal keys count as both.
+func samplesOrdered(data Uint64Interface, l int) (ascending, descending bool) {
+	if presortSamples == 0 {
+		return false, false
+	}
+	step := l / presortSamples
+	if step < 2 {
+		return false, false
+	}
+	ascending, descending = true, true
+	prev := data.Key(0)
+	for i := step; i < l && (ascending || descending); i += step {
+		before, k := data.Key(i-1), data.Key(i)
+		if prev > before || before > k {
+			ascending = false
+		}
+		if prev < before || before < k {
+			descending = false
+		}
+		prev = k
+	}
+	return ascending, descending
+}
+
+// sortPresorted tries to sort data, of length l, without radix passes,
+// and reports whether it did.  If a sample of keys says data is already
+// in order or in reverse order, it's flipped if need be and finished by
+// insertion sort, which is quick when few items are out of place, within
+// a budget of one swap per presortBudget items.  Otherwise, or if the
+// budget runs out, it returns false, leaving data permuted but unsorted,
+// for a radix sort to finish.  On data that isn't presorted this usually costs
+// just a few Key calls, as the sample fails within its first few places.
+func sortPresorted(data Uint64Interface, l int) bool {
+	ascending, descending := samplesOrdered(data, l)
+	switch {
+	case ascending:
+	case descending:
+		Flip(data)
+	default:
+		return false
+	}
+	return insertionRepair(data, l/presortBudget)
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// presortedInputs fill data in orders the presorted check should and
+// shouldn't catch.
+var presortedInputs = []struct {
+	name string
+	fill func(data []uint64)
+}{
+	{"Sorted", func(data []uint64) {
+		for i := range data {
+			data[i] = uint64(i)
+		}
+	}},
+	{"Reversed", func(data []uint64) {
+		for i := range data {
+			data[i] = uint64(len(data) - i)
+		}
+	}},
+	{"NearlySorted", func(data []uint64) {
+		for i := range data {
+			data[i] = uint64(i)
+		}
+		for i := 0; i < len(data)/100; i++ {
+			j := rand.Intn(len(data) - 2)
+			data[j], data[j+2] = data[j+2], data[j]
+		}
+	}},
+	{"FarOutliers", func(data []uint64) {
+		for i := range data {
+			data[i] = uint64(i)
+		}
+		for i := 0; i < 10; i++ {
+			j, k := rand.Intn(len(data)), rand.Intn(len(data))
+			data[j], data[k] = data[k], data[j]
+		}
+	}},
+	{"Random", func(data []uint64) {
+		for i := range data {
+			data[i] = uint64(rand.Int63())
+		}
+	}},
+}
+
+func TestPresorted(t *testing.T) {
+	for _, in := range presortedInputs {
+		for _, n := range []int{100, 1000, 100000} {
+			data := make([]uint64, n)
+			in.fill(data)
+			ByUint64(Uint64Slice(data))
+			if !Uint64sAreSorted(data) {
+				t.Errorf("%s, n=%d: uint64s didn't sort", in.name, n)
+			}
+			ints := make([]int64, n)
+			in.fill(data)
+			for i := range ints {
+				ints[i] = int64(data[i]) - int64(n/2)
+			}
+			ByInt64(Int64Slice(ints))
+			if !sort.IsSorted(Int64Slice(ints)) {
+				t.Errorf("%s, n=%d: int64s didn't sort", in.name, n)
+			}
+		}
+	}
+}
+
+func benchPresorted(b *testing.B, fill func([]uint64), samples int) {
+	defer SetPresortSamples(SetPresortSamples(samples))
+	b.StopTimer()
+	data := make([]uint64, 1<<20)
+	for i := 0; i < b.N; i++ {
+		fill(data)
+		b.StartTimer()
+		ByUint64(Uint64Slice(data))
+		b.StopTimer()
+	}
+}
+
+// BenchmarkPresorted sorts each input with the presorted check on and
+// off.
+func BenchmarkPresorted(b *testing.B) {
+	for _, in := range presortedInputs {
+		in := in
+		b.Run(in.name, func(b *testing.B) { benchPresorted(b, in.fill, 64) })
+		b.Run(in.name+"NoCheck", func(b *testing.B) { benchPresorted(b, in.fill, 0) })
+	}
+}
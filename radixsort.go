@@ -23,35 +23,67 @@ const keyUint64Help = " (for float data, sortutil Key functions may help resolve
 const panicMessage = "sort failed: could be a data race, a bug in package sorts, or a subtle bug in the interface implementation"
 
 // maxRadixDepth limits how deeply the radix part of string sorts can
-// recurse before we bail to quicksort.  Each recursion uses 2KB stack.
+// recurse before we bail to quicksort.  Each recursion uses radixPassBytes
+// of stack.
 const maxRadixDepth = 32
 
+// VerifyResults controls whether the radix sorts check that data is in
+// order after sorting, and panic if it isn't.  The check is an extra pass
+// calling Less about n times; it catches a Less inconsistent with Key, a
+// data race, or a bug in this package, any of which can otherwise leave
+// data silently out of order.  Turning it off is only for hot paths whose
+// interface implementations are already well tested.  Set it once at
+// startup, never while sorts may be running.  The Checked sorts always
+// check, whatever its value.
+var VerifyResults = true
+
 // task describes a range of data to be sorted and additional
 // information the sorter needs: bitshift in a numeric sort, byte offset in
 // a string sort, or maximum depth (expressed as -maxDepth-1) for a
-// quicksort.
-type task struct{ offs, pos, end int }
+// quicksort.  depth counts the radix passes already on the stack above
+// this task, for Options.MaxMemoryBytes; quicksort tasks leave it 0.  cfg
+// holds the sort's Options tunables, or is nil for the defaults.
+type task struct {
+	offs, pos, end, depth int
+	cfg                   *settings
+}
 
 // ByUint64 sorts data by a uint64 key.
 func ByUint64(data Uint64Interface) {
+	mustSort(byUint64(data, parallelSort, VerifyResults, nil))
+}
+
+// byUint64 is ByUint64 with the radix passes started by run and tuned by
+// cfg.  If verify is set, it checks the result and returns a *SortError
+// if it fails.
+func byUint64(data Uint64Interface, run sortRunner, verify bool, cfg *settings) error {
 	l := data.Len()
-	if l < qSortCutoff {
+	if l < cfg.qSortCutoff() {
 		qSort(data, 0, l)
-		return
+		return nil
+	}
+
+	switch {
+	case sortPresorted(data, l):
+	case cfg.wideRadix(l):
+		shift := guessIntShiftBits(data, 0, l, wideRadix)
+		run(data, radixSortUint64Wide, task{offs: int(shift), end: l, cfg: cfg})
+	default:
+		shift := guessIntShift(data, 0, l)
+		run(data, radixSortUint64, task{offs: int(shift), end: l, cfg: cfg})
 	}
-	
-	shift := guessIntShift(data, l)
-	parallelSort(data, radixSortUint64, task{offs: int(shift), end: l})
 
+	if !verify {
+		return nil
+	}
 	// check results if we radix sorted!
-	for i := 1; i < l; i++ {
-		if data.Less(i, i-1) {
-			if data.Key(i) > data.Key(i-1) {
-				panic(keyPanicMessage + keyUint64Help)
-			}
-			panic(panicMessage)
+	if i := firstUnsorted(data, l, cfg.workers(l)); i > 0 {
+		if data.Key(i) > data.Key(i-1) {
+			return &SortError{i, true, keyPanicMessage + keyUint64Help}
 		}
+		return &SortError{i, false, panicMessage}
 	}
+	return nil
 }
 
 // int64Key generates a uint64 from an int64
@@ -67,74 +99,118 @@ func (iw intwrapper) Key(i int) uint64 {
 
 // ByInt64 sorts data by an int64 key.
 func ByInt64(data Int64Interface) {
+	mustSort(byInt64(data, parallelSort, VerifyResults, nil))
+}
+
+// byInt64 is ByInt64 with the radix passes started by run and tuned by
+// cfg.  If verify is set, it checks the result and returns a *SortError
+// if it fails.
+func byInt64(data Int64Interface, run sortRunner, verify bool, cfg *settings) error {
 	l := data.Len()
-	if l < qSortCutoff {
+	if l < cfg.qSortCutoff() {
 		qSort(data, 0, l)
-		return
+		return nil
 	}
 
-	shift := guessIntShift(intwrapper{data}, l)
-	parallelSort(data, radixSortInt64, task{offs: int(shift), end: l})
+	keys := intwrapper{data}
+	switch {
+	case sortPresorted(keys, l):
+	case cfg.wideRadix(l):
+		shift := guessIntShiftBits(keys, 0, l, wideRadix)
+		run(keys, radixSortUint64Wide, task{offs: int(shift), end: l, cfg: cfg})
+	default:
+		shift := guessIntShift(keys, 0, l)
+		run(data, radixSortInt64, task{offs: int(shift), end: l, cfg: cfg})
+	}
 
+	if !verify {
+		return nil
+	}
 	// check results!
-	for i := 1; i < l; i++ {
-		if data.Less(i, i-1) {
-			if data.Key(i) > data.Key(i-1) {
-				panic(keyPanicMessage + keyUint64Help)
-			}
-			panic(panicMessage)
+	if i := firstUnsorted(data, l, cfg.workers(l)); i > 0 {
+		if data.Key(i) > data.Key(i-1) {
+			return &SortError{i, true, keyPanicMessage + keyUint64Help}
 		}
+		return &SortError{i, false, panicMessage}
 	}
+	return nil
 }
 
 // ByString sorts data by a string key.
 func ByString(data StringInterface) {
+	mustSort(byString(data, parallelSort, VerifyResults, nil))
+}
+
+// byString is ByString with the radix passes started by run and tuned by
+// cfg.  If verify is set, it checks the result and returns a *SortError
+// if it fails.
+func byString(data StringInterface, run sortRunner, verify bool, cfg *settings) error {
 	l := data.Len()
-	if l < qSortCutoff {
+	if l < cfg.qSortCutoff() {
 		qSort(data, 0, l)
-		return
+		return nil
 	}
 
-	parallelSort(data, radixSortString, task{end: l})
+	run(data, radixSortString, task{end: l, cfg: cfg})
 
+	if !verify {
+		return nil
+	}
 	// check results if we radix sorted!
-	for i := 1; i < l; i++ {
-		if data.Less(i, i-1) {
-			if data.Key(i) > data.Key(i-1) {
-				panic(keyPanicMessage)
-			}
-			panic(panicMessage)
+	if i := firstUnsorted(data, l, cfg.workers(l)); i > 0 {
+		if data.Key(i) > data.Key(i-1) {
+			return &SortError{i, true, keyPanicMessage}
 		}
+		return &SortError{i, false, panicMessage}
 	}
+	return nil
 }
 
-// ByBytes sorts data by a []byte key.
+// ByBytes sorts data by a []byte key.  Keys are ordered as bytes.Compare
+// orders them, treating each byte as unsigned; see ByBytesSigned for the
+// signed alternative.
 func ByBytes(data BytesInterface) {
+	mustSort(byBytes(data, parallelSort, VerifyResults, nil))
+}
+
+// byBytes is ByBytes with the radix passes started by run and tuned by
+// cfg.  If verify is set, it checks the result and returns a *SortError
+// if it fails.
+func byBytes(data BytesInterface, run sortRunner, verify bool, cfg *settings) error {
 	l := data.Len()
-	if l < qSortCutoff {
+	if l < cfg.qSortCutoff() {
 		qSort(data, 0, l)
-		return
+		return nil
 	}
 
-	parallelSort(data, radixSortBytes, task{end: l})
+	run(data, radixSortBytes, task{end: l, cfg: cfg})
 
+	if !verify {
+		return nil
+	}
 	// check results if we radix sorted!
-	for i := 1; i < l; i++ {
-		if data.Less(i, i-1) {
-			if bytes.Compare(data.Key(i), data.Key(i-1)) > 0 {
-				panic(keyPanicMessage)
-			}
-			panic(panicMessage)
+	if i := firstUnsorted(data, l, cfg.workers(l)); i > 0 {
+		if bytes.Compare(data.Key(i), data.Key(i-1)) > 0 {
+			return &SortError{i, true, keyPanicMessage}
 		}
+		return &SortError{i, false, panicMessage}
 	}
+	return nil
 }
 
 // guessIntShift saves a pass when the data is distributed roughly uniformly
 // in a small range (think shuffled indices into a small array), and rarely
 // hurts much otherwise: either it just returns 64-radix quickly, or it
 // returns too small a shift and the sort notices after one useless counting
-// pass.
-func guessIntShift(data Uint64Interface, l int) uint {
+// pass.  It samples keys in data[a:b].
+func guessIntShift(data Uint64Interface, a, b int) uint {
+	return guessIntShiftBits(data, a, b, radix)
+}
+
+// guessIntShiftBits is guessIntShift for a radix of the given number of
+// bits.
+func guessIntShiftBits(data Uint64Interface, a, b int, bits int) uint {
+	l := b - a
 	step := l >> 5
 	if l > 1<<16 {
 		step = l >> 8
@@ -142,9 +218,9 @@ func guessIntShift(data Uint64Interface, l int) uint {
 	if step == 0 { // only for tests w/qSortCutoff lowered
 		step = 1
 	}
-	min := data.Key(l - 1)
+	min := data.Key(b - 1)
 	max := min
-	for i := 0; i < l; i += step {
+	for i := a; i < b; i += step {
 		k := data.Key(i)
 		if k < min {
 			min = k
@@ -159,7 +235,7 @@ func guessIntShift(data Uint64Interface, l int) uint {
 		log2diff++
 		diff >>= 1
 	}
-	shiftGuess := log2diff - radix
+	shiftGuess := log2diff - bits
 	if shiftGuess < 0 {
 		return 0
 	}
@@ -207,12 +283,16 @@ for laying out American flag sort
 // All three radixSort functions below do a counting pass and a swapping
 // pass, then recurse.  They fall back to comparison sort for small buckets
 // and equal ranges, and the int sorts try to skip bits that are identical
-// across the whole range being sorted.
+// across the whole range being sorted.  Each pass fetches an item's key
+// twice: once counting, and once in the swapping loop, when the item
+// reaches position i and either stays or is swapped straight into its
+// bucket, never to be visited again in that pass.  TestKeyCalls checks
+// this.
 
 func radixSortUint64(dataI sort.Interface, t task, sortRange func(task)) {
 	data := dataI.(Uint64Interface)
 	shift, a, b := uint(t.offs), t.pos, t.end
-	if b-a < qSortCutoff {
+	if b-a < t.cfg.qSortCutoff() {
 		qSort(data, a, b)
 		return
 	}
@@ -220,16 +300,21 @@ func radixSortUint64(dataI sort.Interface, t task, sortRange func(task)) {
 	// use a single pass over the keys to bucket data and find min/max
 	// (for skipping over bits that are always identical)
 	var bucketStarts, bucketEnds [1 << radix]int
-	min := data.Key(a)
-	max := min
-	for i := a; i < b; i++ {
-		k := data.Key(i)
-		bucketStarts[(k>>shift)&mask]++
-		if k < min {
-			min = k
-		}
-		if k > max {
-			max = k
+	var min, max uint64
+	if w := countStripes(t, data.Len()); w > 1 {
+		min, max = parallelCount(data, shift, a, b, w, &bucketStarts)
+	} else {
+		min = data.Key(a)
+		max = min
+		for i := a; i < b; i++ {
+			k := data.Key(i)
+			bucketStarts[(k>>shift)&mask]++
+			if k < min {
+				min = k
+			}
+			if k > max {
+				max = k
+			}
 		}
 	}
 
@@ -250,7 +335,7 @@ func radixSortUint64(dataI sort.Interface, t task, sortRange func(task)) {
 		if nextShift < 0 {
 			nextShift = 0
 		}
-		sortRange(task{nextShift, a, b})
+		sortRange(task{nextShift, a, b, t.depth + 1, t.cfg})
 		return
 	}
 
@@ -293,7 +378,7 @@ func radixSortUint64(dataI sort.Interface, t task, sortRange func(task)) {
 	pos = a
 	for _, end := range bucketEnds {
 		if end > pos+1 {
-			sortRange(task{int(nextShift), pos, end})
+			sortRange(task{int(nextShift), pos, end, t.depth + 1, t.cfg})
 		}
 		pos = end
 	}
@@ -302,7 +387,7 @@ func radixSortUint64(dataI sort.Interface, t task, sortRange func(task)) {
 func radixSortInt64(dataI sort.Interface, t task, sortRange func(task)) {
 	data := dataI.(Int64Interface)
 	shift, a, b := uint(t.offs), t.pos, t.end
-	if b-a < qSortCutoff {
+	if b-a < t.cfg.qSortCutoff() {
 		qSort(data, a, b)
 		return
 	}
@@ -310,16 +395,21 @@ func radixSortInt64(dataI sort.Interface, t task, sortRange func(task)) {
 	// use a single pass over the keys to bucket data and find min/max
 	// (for skipping over bits that are always identical)
 	var bucketStarts, bucketEnds [1 << radix]int
-	min := int64Key(data.Key(a))
-	max := min
-	for i := a; i < b; i++ {
-		k := int64Key(data.Key(i))
-		bucketStarts[(k>>shift)&mask]++
-		if k < min {
-			min = k
-		}
-		if k > max {
-			max = k
+	var min, max uint64
+	if w := countStripes(t, data.Len()); w > 1 {
+		min, max = parallelCount(intwrapper{data}, shift, a, b, w, &bucketStarts)
+	} else {
+		min = int64Key(data.Key(a))
+		max = min
+		for i := a; i < b; i++ {
+			k := int64Key(data.Key(i))
+			bucketStarts[(k>>shift)&mask]++
+			if k < min {
+				min = k
+			}
+			if k > max {
+				max = k
+			}
 		}
 	}
 
@@ -340,7 +430,7 @@ func radixSortInt64(dataI sort.Interface, t task, sortRange func(task)) {
 		if nextShift < 0 {
 			nextShift = 0
 		}
-		sortRange(task{nextShift, a, b})
+		sortRange(task{nextShift, a, b, t.depth + 1, t.cfg})
 		return
 	}
 
@@ -384,7 +474,7 @@ func radixSortInt64(dataI sort.Interface, t task, sortRange func(task)) {
 	pos = a
 	for _, end := range bucketEnds {
 		if end > pos+1 {
-			sortRange(task{int(nextShift), pos, end})
+			sortRange(task{int(nextShift), pos, end, t.depth + 1, t.cfg})
 		}
 		pos = end
 	}
@@ -398,11 +488,11 @@ func radixSortString(dataI sort.Interface, t task, sortRange func(task)) {
 		quickSortWorker(data, t, sortRange)
 		return
 	}
-	if b-a < qSortCutoff {
+	if b-a < t.cfg.qSortCutoff() {
 		qSort(data, a, b)
 		return
 	}
-	if offset == maxRadixDepth {
+	if offset >= t.cfg.maxRadixDepth() {
 		qSortPar(data, t, sortRange)
 		return
 	}
@@ -410,15 +500,18 @@ func radixSortString(dataI sort.Interface, t task, sortRange func(task)) {
 	// swap too-short strings to start and count bucket sizes
 	bucketStarts, bucketEnds := [256]int{}, [256]int{}
 	aInitial := a
-	for i := a; i < b; i++ {
-		k := data.Key(i)
-		if len(k) <= offset {
-			// swap too-short strings to start
-			data.Swap(a, i)
-			a++
-			continue
+	if w := countStripes(t, data.Len()); w <= 1 ||
+		!parallelCountKeys(data, offset, a, b, w, &bucketStarts) {
+		for i := a; i < b; i++ {
+			k := data.Key(i)
+			if len(k) <= offset {
+				// swap too-short strings to start
+				data.Swap(a, i)
+				a++
+				continue
+			}
+			bucketStarts[k[offset]]++
 		}
-		bucketStarts[k[offset]]++
 	}
 	if a > aInitial+1 {
 		qSortEqualKeyRange(data, aInitial, a)
@@ -431,7 +524,7 @@ func radixSortString(dataI sort.Interface, t task, sortRange func(task)) {
 		bucketEnds[i] = pos
 		if bucketStarts[i] == a && bucketEnds[i] == b {
 			// everything was in the same bucket
-			sortRange(task{offset + 1, a, b})
+			sortRange(task{offset + 1, a, b, t.depth + 1, t.cfg})
 			return
 		}
 	}
@@ -451,7 +544,7 @@ func radixSortString(dataI sort.Interface, t task, sortRange func(task)) {
 			bucketStarts[destBucket]++
 		}
 		if i > start+1 {
-			sortRange(task{offset + 1, start, i})
+			sortRange(task{offset + 1, start, i, t.depth + 1, t.cfg})
 		}
 	}
 }
@@ -464,11 +557,11 @@ func radixSortBytes(dataI sort.Interface, t task, sortRange func(task)) {
 		quickSortWorker(data, t, sortRange)
 		return
 	}
-	if b-a < qSortCutoff {
+	if b-a < t.cfg.qSortCutoff() {
 		qSort(data, a, b)
 		return
 	}
-	if offset == maxRadixDepth {
+	if offset >= t.cfg.maxRadixDepth() {
 		qSortPar(data, t, sortRange)
 		return
 	}
@@ -476,15 +569,18 @@ func radixSortBytes(dataI sort.Interface, t task, sortRange func(task)) {
 	// swap too-short strings to start and count bucket sizes
 	bucketStarts, bucketEnds := [256]int{}, [256]int{}
 	aInitial := a
-	for i := a; i < b; i++ {
-		k := data.Key(i)
-		if len(k) <= offset {
-			// swap too-short strings to start
-			data.Swap(a, i)
-			a++
-			continue
+	if w := countStripes(t, data.Len()); w <= 1 ||
+		!parallelCountKeys(data, offset, a, b, w, &bucketStarts) {
+		for i := a; i < b; i++ {
+			k := data.Key(i)
+			if len(k) <= offset {
+				// swap too-short strings to start
+				data.Swap(a, i)
+				a++
+				continue
+			}
+			bucketStarts[k[offset]]++
 		}
-		bucketStarts[k[offset]]++
 	}
 	if a > aInitial+1 {
 		qSortEqualKeyRange(data, aInitial, a)
@@ -497,7 +593,7 @@ func radixSortBytes(dataI sort.Interface, t task, sortRange func(task)) {
 		bucketEnds[i] = pos
 		if bucketStarts[i] == a && bucketEnds[i] == b {
 			// everything was in the same bucket
-			sortRange(task{offset + 1, a, b})
+			sortRange(task{offset + 1, a, b, t.depth + 1, t.cfg})
 			return
 		}
 	}
@@ -517,7 +613,7 @@ func radixSortBytes(dataI sort.Interface, t task, sortRange func(task)) {
 			bucketStarts[destBucket]++
 		}
 		if i > start+1 {
-			sortRange(task{offset + 1, start, i})
+			sortRange(task{offset + 1, start, i, t.depth + 1, t.cfg})
 		}
 	}
 }
@@ -9,10 +9,12 @@ package sorts_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sort"
 	"strconv"
+	"sync/atomic"
 	"testing"
 
 	. "github.com/twotwotwo/sorts"
@@ -101,6 +103,53 @@ func TestSortCheck(t *testing.T) {
 	})
 }
 
+func TestSortCheckErrors(t *testing.T) {
+	defer SetQSortCutoff(SetQSortCutoff(1))
+	for _, c := range []struct {
+		name        string
+		sort        func() error
+		index       int
+		keyMismatch bool
+	}{
+		{"unsortableInts", func() error { return ByInt64Checked(unsortableInts{IntSlice{1, 1, 1}}) }, 2, false},
+		{"unsortableStrings", func() error { return ByStringChecked(unsortableStrings{StringSlice{"", "", ""}}) }, 2, false},
+		{"miskeyedUints", func() error { return ByUint64Checked(miskeyedUints{UintSlice{1, 2, 3}}) }, 1, true},
+		{"miskeyedBytes", func() error {
+			return ByBytesChecked(miskeyedBytes{BytesSlice{[]byte{'a'}, []byte{'b'}, []byte{'c'}}})
+		}, 1, true},
+	} {
+		var se *SortError
+		if err := c.sort(); !errors.As(err, &se) {
+			t.Errorf("%s: got %v, want a *SortError", c.name, err)
+			continue
+		}
+		if se.Index != c.index || se.KeyMismatch != c.keyMismatch {
+			t.Errorf("%s: got Index %d, KeyMismatch %v", c.name, se.Index, se.KeyMismatch)
+		}
+	}
+	if err := ByUint64Checked(UintSlice{3, 2, 1}); err != nil {
+		t.Errorf("sortable data: got %v", err)
+	}
+}
+
+func TestVerifyResults(t *testing.T) {
+	defer SetQSortCutoff(SetQSortCutoff(1))
+	VerifyResults = false
+	defer func() { VerifyResults = true }()
+	ByInt64(unsortableInts{IntSlice{1, 1, 1}}) // would panic if checked
+	if ByInt64Checked(unsortableInts{IntSlice{1, 1, 1}}) == nil {
+		t.Errorf("Checked sort skipped its check")
+	}
+	data := make([]int, 10000)
+	for i := range data {
+		data[i] = rand.Int()
+	}
+	Ints(data)
+	if !IntsAreSorted(data) {
+		t.Errorf("didn't sort with VerifyResults off")
+	}
+}
+
 func TestFlip(t *testing.T) {
 	data1, expected1 := [...]int{1, 2, 3, 4, 5}, [...]int{5, 4, 3, 2, 1}
 	Flip(IntSlice(data1[:]))
@@ -274,6 +323,80 @@ func BenchmarkSortInt64K(b *testing.B) {
 	}
 }
 
+// costlyKeys is a Uint64Slice whose Key does some pointless work, standing in
+// for one that decodes its key from a record, and counts its calls.
+type costlyKeys struct {
+	Uint64Slice
+	calls *int64
+}
+
+func (p costlyKeys) Key(i int) uint64 {
+	atomic.AddInt64(p.calls, 1)
+	k := p.Uint64Slice[i]
+	for j := 0; j < 8; j++ { // eight 8-bit rotations: the same k
+		k = k>>8 | k<<56
+	}
+	return k
+}
+
+func (p costlyKeys) Less(i, j int) bool { return p.Uint64Slice[i] < p.Uint64Slice[j] }
+
+// The radix passes fetch each key once to count buckets and once to move
+// it, so random 64-bit keys, which need two passes at this size, should
+// cost about four Key calls per item.
+func TestKeyCalls(t *testing.T) {
+	var calls int64
+	data := costlyKeys{make(Uint64Slice, 1<<16), &calls}
+	for i := range data.Uint64Slice {
+		data.Uint64Slice[i] = uint64(rand.Int63())
+	}
+	ByUint64(data)
+	if perItem := float64(calls) / float64(data.Len()); perItem > 4.5 {
+		t.Errorf("%.2f Key calls per item", perItem)
+	}
+}
+
+func BenchmarkCostlyKey(b *testing.B) {
+	b.StopTimer()
+	var calls int64
+	data := costlyKeys{make(Uint64Slice, 1<<16), &calls}
+	for i := 0; i < b.N; i++ {
+		for i := range data.Uint64Slice {
+			data.Uint64Slice[i] = uint64(rand.Int63())
+		}
+		b.StartTimer()
+		ByUint64(data)
+		b.StopTimer()
+	}
+	b.ReportMetric(float64(calls)/float64(b.N*data.Len()), "keys/item")
+}
+
+// benchTiny sorts lots of 2- to 16-item slices, which go straight to the
+// small-range fallback, with insertionCutoff set to cutoff.
+func benchTiny(b *testing.B, cutoff int) {
+	defer SetInsertionCutoff(SetInsertionCutoff(cutoff))
+	b.StopTimer()
+	slices := make([][]int, 1000)
+	for i := range slices {
+		slices[i] = make([]int, 2+i%15)
+	}
+	for i := 0; i < b.N; i++ {
+		for _, data := range slices {
+			for j := range data {
+				data[j] = rand.Int()
+			}
+		}
+		b.StartTimer()
+		for _, data := range slices {
+			Ints(data)
+		}
+		b.StopTimer()
+	}
+}
+
+func BenchmarkTinySorts(b *testing.B)            { benchTiny(b, 12) }
+func BenchmarkTinySortsNoInsertion(b *testing.B) { benchTiny(b, 0) }
+
 const (
 	_Sawtooth = iota
 	_Rand
@@ -460,6 +583,55 @@ func TestBackshift(t *testing.T) {
 	}
 }
 
+// TestUint64Extremes checks guessIntShift and the radix sort at the ends of
+// the uint64 range, including an outlier at MaxUint64 the sampling misses,
+// which forces the sort to notice its shift was too small and back up.
+func TestUint64Extremes(t *testing.T) {
+	const n = 1000
+	const max = ^uint64(0)
+
+	bothEnds := make([]uint64, n)
+	for i := range bothEnds {
+		if i%2 == 0 {
+			bothEnds[i] = max
+		}
+	}
+	if s := GuessUint64Shift(Uint64Slice(bothEnds), n); s != 64-8 {
+		t.Errorf("guessed shift %d for 0 and MaxUint64, want 56", s)
+	}
+
+	allMax := make([]uint64, n)
+	for i := range allMax {
+		allMax[i] = max
+	}
+	if s := GuessUint64Shift(Uint64Slice(allMax), n); s != 0 {
+		t.Errorf("guessed shift %d for all-equal keys, want 0", s)
+	}
+
+	outlier := make([]uint64, n)
+	for i := range outlier {
+		outlier[i] = uint64(i % 100)
+	}
+	outlier[1] = max // not on a sampled index
+	if s := GuessUint64Shift(Uint64Slice(outlier), n); s != 0 {
+		t.Fatalf("guessIntShift found the outlier (shift %d); move it", s)
+	}
+
+	for name, data := range map[string][]uint64{
+		"0 and MaxUint64": bothEnds,
+		"all MaxUint64":   allMax,
+		"missed outlier":  outlier,
+	} {
+		forceRadix(Uint64Slice(data).Sort)
+		if !Uint64sAreSorted(data) {
+			t.Errorf("%s: didn't sort", name)
+		}
+	}
+	if outlier[n-1] != max || outlier[n-2] != 99 {
+		t.Errorf("missed outlier: sorted to %d, %d at end", outlier[n-2], outlier[n-1])
+	}
+}
+
 // TestFwdShift uses data that lets the radix sort shift past some bits in
 // the middle; it might catch if it broke the sort.
 func TestFwdShift(t *testing.T) {
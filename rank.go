@@ -0,0 +1,43 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// A TieRule says what rank RanksUint64 gives items that are tied.
+type TieRule int
+
+const (
+	// MinRank gives each tied item the lowest rank in its run, as in
+	// "1224" competition ranking (but 0-based).
+	MinRank TieRule = iota
+	// AverageRank gives each tied item the mean of the ranks its run
+	// covers, as rank correlation statistics like Spearman's rho expect.
+	AverageRank
+)
+
+// RanksUint64 returns the 0-based rank of each item of data in sorted
+// order: ranks[i] is where data's item i would land if data were sorted
+// by ByUint64, with ties settled by rule.  Items are tied when neither is
+// Less than the other.  Like ArgsortUint64, which it uses, it leaves data
+// in place.
+func RanksUint64(data Uint64Interface, rule TieRule) []float64 {
+	perm := ArgsortUint64(data)
+	ranks := make([]float64, len(perm))
+	start := 0
+	for i := 1; i <= len(perm); i++ {
+		if i < len(perm) && !data.Less(perm[start], perm[i]) && !data.Less(perm[i], perm[start]) {
+			continue
+		}
+		rank := float64(start)
+		if rule == AverageRank {
+			rank = float64(start+i-1) / 2
+		}
+		for _, j := range perm[start:i] {
+			ranks[j] = rank
+		}
+		start = i
+	}
+	return ranks
+}
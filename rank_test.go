@@ -0,0 +1,50 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestRanksUint64(t *testing.T) {
+	small := Uint64Slice{30, 10, 20, 10, 40, 10}
+	if got, want := fmt.Sprint(RanksUint64(small, MinRank)), "[4 0 3 0 5 0]"; got != want {
+		t.Errorf("MinRank: got %s, want %s", got, want)
+	}
+	if got, want := fmt.Sprint(RanksUint64(small, AverageRank)), "[4 1 3 1 5 1]"; got != want {
+		t.Errorf("AverageRank: got %s, want %s", got, want)
+	}
+
+	// reference: count how many items are smaller, and how many are equal
+	data := make(Uint64Slice, 2000)
+	for i := range data {
+		data[i] = uint64(rand.Intn(500))
+	}
+	varyQSortCutoff(func() {
+		minRanks, avgRanks := RanksUint64(data, MinRank), RanksUint64(data, AverageRank)
+		for i, v := range data {
+			less, equal := 0, 0
+			for _, w := range data {
+				if w < v {
+					less++
+				} else if w == v {
+					equal++
+				}
+			}
+			if minRanks[i] != float64(less) {
+				t.Fatalf("MinRank: item %d got rank %g, want %d", i, minRanks[i], less)
+			}
+			if want := float64(less) + float64(equal-1)/2; avgRanks[i] != want {
+				t.Fatalf("AverageRank: item %d got rank %g, want %g", i, avgRanks[i], want)
+			}
+		}
+	})
+}
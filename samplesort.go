@@ -0,0 +1,122 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"sort"
+	"sync"
+)
+
+// sampleBucketsPerWorker is how many buckets ByStringSampled splits data
+// into per worker, so a worker that finishes a small bucket has others
+// to take.
+const sampleBucketsPerWorker = 4
+
+// sampleSize is how many keys ByStringSampled samples per bucket to pick
+// its splitters from.
+const sampleSize = 16
+
+// ByStringSampled sorts data by string key like ByString, but splits the
+// work among goroutines with a sample sort instead of by first byte.
+// ByString hands out buckets as each radix pass makes them, so when most
+// keys share a first byte, or a few, one goroutine sorts nearly
+// everything before there's anything to hand out.  ByStringSampled
+// instead sorts a sample of evenly spaced keys, picks the splitters that
+// cut it into a few equal buckets per worker, and moves every item into
+// its bucket in one pass (counting the bucket sizes in parallel first);
+// the buckets then hold about equal numbers of items whatever the keys'
+// prefixes, and each is radix sorted as ByString would, in parallel.
+//
+// Each item's bucket is found by a binary search over the splitters, so
+// the partitioning pass costs more than a radix pass; with one worker
+// (see MaxProcs), or data under the size ByString would sort in
+// parallel, it just calls ByString.
+func ByStringSampled(data StringInterface) {
+	mustSort(byString(data, sampledRunner, VerifyResults, nil))
+}
+
+// sampledRunner is a sortRunner that sample sorts data into buckets, then
+// runs sorter on each bucket in parallel.
+func sampledRunner(data sort.Interface, sorter sortFunc, initialTask task) {
+	l := initialTask.end - initialTask.pos
+	w := initialTask.cfg.workers(l)
+	if w == 1 {
+		parallelSort(data, sorter, initialTask)
+		return
+	}
+	var once sync.Once
+	parallelSort(data, func(data sort.Interface, t task, sortRange func(task)) {
+		first := false
+		once.Do(func() { first = true })
+		if !first {
+			sorter(data, t, sortRange)
+			return
+		}
+		// the initial task partitions
+		for _, b := range sampleBuckets(data.(StringInterface), t.pos, t.end, w) {
+			if b.end-b.pos > 1 {
+				sortRange(task{t.offs, b.pos, b.end, t.depth + 1, t.cfg})
+			}
+		}
+	}, initialTask)
+}
+
+// sampleBuckets moves items a through b-1 of data into about
+// sampleBucketsPerWorker*w buckets, split at keys sampled from them, and
+// returns the buckets as tasks, in order.
+func sampleBuckets(data StringInterface, a, b, w int) []task {
+	// sort a sample and take evenly spaced splitters from it
+	n := sampleBucketsPerWorker * w
+	sample := make([]string, n*sampleSize)
+	for i := range sample {
+		sample[i] = data.Key(a + (b-a)/len(sample)*i)
+	}
+	sort.Strings(sample)
+	splitters := make([]string, 0, n-1)
+	for i := sampleSize; i < len(sample); i += sampleSize {
+		if len(splitters) == 0 || sample[i] != splitters[len(splitters)-1] {
+			splitters = append(splitters, sample[i])
+		}
+	}
+	bucketOf := func(k string) int {
+		return sort.Search(len(splitters), func(j int) bool { return splitters[j] > k })
+	}
+
+	// count bucket sizes, each stripe in its own table
+	counts := make([][]int, w)
+	inStripes(a, b, w, func(s, lo, hi int) {
+		c := make([]int, len(splitters)+1)
+		for i := lo; i < hi; i++ {
+			c[bucketOf(data.Key(i))]++
+		}
+		counts[s] = c
+	})
+	buckets := make([]task, len(splitters)+1)
+	starts := make([]int, len(buckets))
+	pos := a
+	for i := range buckets {
+		starts[i] = pos
+		for _, c := range counts {
+			pos += c[i]
+		}
+		buckets[i] = task{pos: starts[i], end: pos}
+	}
+
+	// swap each item into its bucket, as radixSortString does
+	for cur := range buckets {
+		i, end := starts[cur], buckets[cur].end
+		for i < end {
+			dest := bucketOf(data.Key(i))
+			if dest != cur {
+				data.Swap(i, starts[dest])
+			} else {
+				i++
+			}
+			starts[dest]++
+		}
+	}
+	return buckets
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByStringSampled(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	n := 100000
+	for _, c := range []struct {
+		name string
+		key  func() string
+	}{
+		{"random", func() string { return randomWords(1)[0] }},
+		// nearly every key has the same first bytes, which ByString
+		// can't split across workers until it's past them
+		{"shared prefix", func() string { return "http://example.com/" + randomWords(1)[0] }},
+		// past the radix depth limit, where ByString quicksorts
+		{"long prefix", func() string { return strings.Repeat("x", 40) + randomWords(1)[0] }},
+		{"few values", func() string { return strings.Repeat("a", rand.Intn(3)) }},
+	} {
+		data := make([]string, n)
+		for i := range data {
+			data[i] = c.key()
+		}
+		ByStringSampled(StringSlice(data))
+		if !StringsAreSorted(data) {
+			t.Errorf("%s: didn't sort", c.name)
+		}
+	}
+
+	// one worker, or small data, is ByString
+	runtime.GOMAXPROCS(1)
+	data := randomWords(20000)
+	ByStringSampled(StringSlice(data))
+	if !StringsAreSorted(data) {
+		t.Errorf("serial: didn't sort")
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// SearchUint64 returns the index of the first item of data whose Key is
+// at least target, or data.Len() if there's none, by binary search.
+// data must already be sorted by key, as ByUint64 leaves it; if it isn't,
+// the result is meaningless.  Only Len and Key are called, so a read-only
+// view of the data works.  Among equal keys, the leftmost is found.
+func SearchUint64(data Uint64Interface, target uint64) int {
+	return sort.Search(data.Len(), func(i int) bool { return data.Key(i) >= target })
+}
+
+// SearchUint64Descending is SearchUint64 for data sorted largest-first, as
+// by ByUint64Descending: it returns the index of the first item whose Key
+// is at most target, or data.Len() if there's none.
+func SearchUint64Descending(data Uint64Interface, target uint64) int {
+	return sort.Search(data.Len(), func(i int) bool { return data.Key(i) <= target })
+}
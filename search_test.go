@@ -0,0 +1,42 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSearchUint64(t *testing.T) {
+	asc := Uint64Slice{10, 20, 20, 20, 30}
+	desc := Uint64Slice{30, 20, 20, 20, 10}
+	for _, c := range []struct {
+		target            uint64
+		wantAsc, wantDesc int
+	}{
+		{5, 0, 5},
+		{10, 0, 4},
+		{15, 1, 4},
+		{20, 1, 1},
+		{30, 4, 0},
+		{35, 5, 0},
+	} {
+		if got := SearchUint64(asc, c.target); got != c.wantAsc {
+			t.Errorf("SearchUint64(%d) = %d, want %d", c.target, got, c.wantAsc)
+		}
+		if got := SearchUint64Descending(desc, c.target); got != c.wantDesc {
+			t.Errorf("SearchUint64Descending(%d) = %d, want %d", c.target, got, c.wantDesc)
+		}
+	}
+	if got := SearchUint64(Uint64Slice(nil), 1); got != 0 {
+		t.Errorf("empty: got %d, want 0", got)
+	}
+	if got := SearchUint64Descending(Uint64Slice(nil), 1); got != 0 {
+		t.Errorf("empty descending: got %d, want 0", got)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// Selection: radix-partitioning data just enough to find an item at a given
+// rank, without sorting the rest.
+
+// partitionUint64 moves the items of data[a:] into buckets by the radix
+// digit at shift, given counts[d] items with each digit d, and returns
+// where each bucket ends.
+func partitionUint64(data Uint64Interface, shift uint, a int, counts *[1 << radix]int) (bucketEnds [1 << radix]int) {
+	var bucketStarts [1 << radix]int
+	pos := a
+	for i, c := range counts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+	}
+
+	for curBucket, bucketEnd := range bucketEnds {
+		i := bucketStarts[curBucket]
+		for i < bucketEnd {
+			destBucket := (data.Key(i) >> shift) & mask
+			if destBucket == uint64(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			data.Swap(i, bucketStarts[destBucket])
+			bucketStarts[destBucket]++
+		}
+	}
+	return
+}
+
+// WeightedSelectUint64 finds the item where the running total of weights,
+// taken in key order, first reaches fraction of the total weight, and
+// returns its index; a fraction of 0.5 gives the weighted median.  It
+// partitions data by radix passes, only descending into the bucket holding
+// the answer, instead of sorting everything.
+//
+// weight(i) must return the weight of whichever item is at index i when
+// it's called, so it has to follow items as data.Swap moves them (for
+// instance, by reading a field of the item).  Weights must not be
+// negative.
+//
+// Afterwards, items before the returned index have keys no greater than
+// its key and items after have keys no smaller; beyond that, data is in no
+// particular order.  It returns -1 if data is empty.
+func WeightedSelectUint64(data Uint64Interface, weight func(i int) float64, fraction float64) int {
+	l := data.Len()
+	if l == 0 {
+		return -1
+	}
+	total := 0.0
+	for i := 0; i < l; i++ {
+		total += weight(i)
+	}
+	target := fraction * total
+
+	a, b := 0, l
+	before := 0.0 // weight of everything in data[:a]
+	for b-a >= qSortCutoff {
+		var counts [1 << radix]int
+		var weights [1 << radix]float64
+
+		// find bits that vary in this range
+		min := data.Key(a)
+		max := min
+		for i := a; i < b; i++ {
+			k := data.Key(i)
+			if k < min {
+				min = k
+			}
+			if k > max {
+				max = k
+			}
+		}
+		diff := min ^ max
+		if diff == 0 {
+			qSortEqualKeyRange(data, a, b)
+			break
+		}
+		log2diff := 0
+		for diff != 0 {
+			log2diff++
+			diff >>= 1
+		}
+		shift := uint(0)
+		if log2diff > radix {
+			shift = uint(log2diff - radix)
+		}
+
+		for i := a; i < b; i++ {
+			d := (data.Key(i) >> shift) & mask
+			counts[d]++
+			weights[d] += weight(i)
+		}
+		bucketEnds := partitionUint64(data, shift, a, &counts)
+
+		// descend into the bucket where the running total crosses target;
+		// if rounding keeps it from ever crossing, take the last bucket
+		start, rangeEnd := a, b
+		for d, end := range bucketEnds {
+			if end > start {
+				a, b = start, end
+				if before+weights[d] >= target || end == rangeEnd {
+					break
+				}
+				before += weights[d]
+			}
+			start = end
+		}
+	}
+	if b-a < qSortCutoff {
+		qSort(data, a, b)
+	}
+
+	for i := a; i < b; i++ {
+		before += weight(i)
+		if before >= target {
+			return i
+		}
+	}
+	return b - 1
+}
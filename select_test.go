@@ -0,0 +1,74 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+type weighted struct {
+	key    uint64
+	weight float64
+}
+
+type weightedSlice []weighted
+
+func (p weightedSlice) Len() int           { return len(p) }
+func (p weightedSlice) Less(i, j int) bool { return p[i].key < p[j].key }
+func (p weightedSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p weightedSlice) Key(i int) uint64   { return p[i].key }
+
+// weightedSelectReference sorts a copy of data and scans it.
+func weightedSelectReference(data weightedSlice, fraction float64) uint64 {
+	sorted := append(weightedSlice(nil), data...)
+	sort.Sort(sorted)
+	total := 0.0
+	for _, w := range sorted {
+		total += w.weight
+	}
+	sum := 0.0
+	for _, w := range sorted {
+		sum += w.weight
+		if sum >= fraction*total {
+			return w.key
+		}
+	}
+	return sorted[len(sorted)-1].key
+}
+
+func TestWeightedSelectUint64(t *testing.T) {
+	if WeightedSelectUint64(weightedSlice(nil), nil, .5) != -1 {
+		t.Errorf("expected -1 selecting from nothing")
+	}
+	for _, n := range []int{1, 10, 1000, 100000} {
+		for _, spread := range []int64{1, 100, 1 << 40} {
+			data := make(weightedSlice, n)
+			for i := range data {
+				// integer weights keep the sums exact
+				data[i] = weighted{uint64(rand.Int63n(spread)), float64(rand.Intn(10))}
+			}
+			for _, fraction := range []float64{0, .1, .5, .99, 1} {
+				want := weightedSelectReference(data, fraction)
+				varyQSortCutoff(func() {
+					d := append(weightedSlice(nil), data...)
+					i := WeightedSelectUint64(d, func(i int) float64 { return d[i].weight }, fraction)
+					if d[i].key != want {
+						t.Fatalf("n=%d spread=%d fraction=%f: selected key %d, want %d", n, spread, fraction, d[i].key, want)
+					}
+					for j := range d {
+						if j < i && d[j].key > d[i].key || j > i && d[j].key < d[i].key {
+							t.Fatalf("n=%d spread=%d fraction=%f: item %d on the wrong side of %d", n, spread, fraction, j, i)
+						}
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByInt64Partitioned(t *testing.T) {
+	for _, negFraction := range []float64{0, .001, .5, 1} {
+		data := make([]int64, 10000)
+		for i := range data {
+			data[i] = rand.Int63n(1 << 20)
+			if rand.Float64() < negFraction {
+				data[i] = -data[i] - 1
+			}
+		}
+		data[0], data[1] = math.MinInt64, math.MaxInt64
+		varyQSortCutoff(func() {
+			rand.Shuffle(len(data), Int64Slice(data).Swap)
+			ByInt64Partitioned(Int64Slice(data))
+			if !Int64sAreSorted(data) {
+				t.Errorf("negFraction=%f: didn't sort", negFraction)
+			}
+		})
+	}
+}
+
+func benchSigned(b *testing.B, sorter func(Int64Interface), negFraction float64) {
+	b.StopTimer()
+	data := make([]int64, 1<<16)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = rand.Int63n(1 << 24)
+			if rand.Float64() < negFraction {
+				data[i] = -data[i]
+			}
+		}
+		b.StartTimer()
+		sorter(Int64Slice(data))
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSignFlipFewNegative(b *testing.B)     { benchSigned(b, ByInt64, .01) }
+func BenchmarkPartitionedFewNegative(b *testing.B)  { benchSigned(b, ByInt64Partitioned, .01) }
+func BenchmarkSignFlipHalfNegative(b *testing.B)    { benchSigned(b, ByInt64, .5) }
+func BenchmarkPartitionedHalfNegative(b *testing.B) { benchSigned(b, ByInt64Partitioned, .5) }
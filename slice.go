@@ -0,0 +1,99 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+const sliceKindMessage = "sorts: Slice sorts need a slice, not a "
+
+// reflectSlice supplies Len and Swap for any slice through reflection.
+type reflectSlice struct {
+	n    int
+	swap func(i, j int)
+}
+
+// newReflectSlice wraps slice, panicking if it isn't one.
+func newReflectSlice(slice interface{}) reflectSlice {
+	v := reflect.ValueOf(slice)
+	if v.Kind() != reflect.Slice {
+		panic(sliceKindMessage + fmt.Sprintf("%T", slice))
+	}
+	return reflectSlice{v.Len(), reflect.Swapper(slice)}
+}
+
+func (s reflectSlice) Len() int      { return s.n }
+func (s reflectSlice) Swap(i, j int) { s.swap(i, j) }
+
+// sliceUint64 sorts a reflectSlice by a key func.
+type sliceUint64 struct {
+	reflectSlice
+	key func(i int) uint64
+}
+
+func (s sliceUint64) Key(i int) uint64   { return s.key(i) }
+func (s sliceUint64) Less(i, j int) bool { return s.key(i) < s.key(j) }
+
+// sliceInt64 sorts a reflectSlice by a key func.
+type sliceInt64 struct {
+	reflectSlice
+	key func(i int) int64
+}
+
+func (s sliceInt64) Key(i int) int64    { return s.key(i) }
+func (s sliceInt64) Less(i, j int) bool { return s.key(i) < s.key(j) }
+
+// sliceString sorts a reflectSlice by a key func.
+type sliceString struct {
+	reflectSlice
+	key func(i int) string
+}
+
+func (s sliceString) Key(i int) string   { return s.key(i) }
+func (s sliceString) Less(i, j int) bool { return s.key(i) < s.key(j) }
+
+// sliceBytes sorts a reflectSlice by a key func.
+type sliceBytes struct {
+	reflectSlice
+	key func(i int) []byte
+}
+
+func (s sliceBytes) Key(i int) []byte   { return s.key(i) }
+func (s sliceBytes) Less(i, j int) bool { return bytes.Compare(s.key(i), s.key(j)) < 0 }
+
+// SliceByUint64 sorts slice by the uint64 key that key returns for the
+// item currently at index i, like sort.Slice but radix sorting, so a
+// []T can be sorted without writing a Uint64Interface for it:
+//
+//	sorts.SliceByUint64(people, func(i int) uint64 { return people[i].ID })
+//
+// Ties are broken arbitrarily; Less is key(i) < key(j), so the check
+// afterwards can't fail because of an inconsistent key.  Swaps go through
+// reflection, which costs something: BenchmarkSliceByUint64 in
+// slice_test.go takes about twice as long as the hand-written interface
+// in BenchmarkInterfaceByUint64.  When that matters, implement
+// Uint64Interface and call ByUint64.  It panics if slice isn't a slice.
+func SliceByUint64(slice interface{}, key func(i int) uint64) {
+	ByUint64(sliceUint64{newReflectSlice(slice), key})
+}
+
+// SliceByString is SliceByUint64 for string keys.
+func SliceByString(slice interface{}, key func(i int) string) {
+	ByString(sliceString{newReflectSlice(slice), key})
+}
+
+// SliceByInt64 is SliceByUint64 for int64 keys.
+func SliceByInt64(slice interface{}, key func(i int) int64) {
+	ByInt64(sliceInt64{newReflectSlice(slice), key})
+}
+
+// SliceByBytes is SliceByUint64 for []byte keys.
+func SliceByBytes(slice interface{}, key func(i int) []byte) {
+	ByBytes(sliceBytes{newReflectSlice(slice), key})
+}
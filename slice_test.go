@@ -0,0 +1,111 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+type record struct {
+	id   uint64
+	name string
+}
+
+// records is the hand-written interface SliceByUint64 saves writing.
+type records []record
+
+func (r records) Len() int           { return len(r) }
+func (r records) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r records) Less(i, j int) bool { return r[i].id < r[j].id }
+func (r records) Key(i int) uint64   { return r[i].id }
+
+func randomRecords(n int) []record {
+	recs := make([]record, n)
+	for i := range recs {
+		id := rand.Intn(n)
+		recs[i] = record{uint64(id), strconv.Itoa(id)}
+	}
+	return recs
+}
+
+// checkRecordSorts sorts random records of a few sizes with sorter, under
+// varyQSortCutoff, and fails if any adjacent pair comes out with before(b,
+// a) or if a record's id and name got separated.
+func checkRecordSorts(t *testing.T, name string, sorter func([]record), before func(a, b record) bool) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		recs := randomRecords(n)
+		varyQSortCutoff(func() {
+			sorter(recs)
+			for i := 1; i < n; i++ {
+				if before(recs[i], recs[i-1]) {
+					t.Fatalf("n=%d: %s: out of order at %d", n, name, i)
+				}
+			}
+			for _, r := range recs {
+				if r.name != strconv.Itoa(int(r.id)) {
+					t.Fatalf("n=%d: %s: fields separated", n, name)
+				}
+			}
+		})
+	}
+}
+
+func byID(a, b record) bool   { return a.id < b.id }
+func byName(a, b record) bool { return a.name < b.name }
+
+func TestSliceSorts(t *testing.T) {
+	checkRecordSorts(t, "SliceByUint64", func(recs []record) {
+		SliceByUint64(recs, func(i int) uint64 { return recs[i].id })
+	}, byID)
+	checkRecordSorts(t, "SliceByString", func(recs []record) {
+		SliceByString(recs, func(i int) string { return recs[i].name })
+	}, byName)
+	checkRecordSorts(t, "SliceByInt64", func(recs []record) {
+		SliceByInt64(recs, func(i int) int64 { return -int64(recs[i].id) })
+	}, func(a, b record) bool { return a.id > b.id })
+	checkRecordSorts(t, "SliceByBytes", func(recs []record) {
+		SliceByBytes(recs, func(i int) []byte { return []byte(recs[i].name) })
+	}, byName)
+}
+
+func TestSliceNotSlice(t *testing.T) {
+	for _, notSlice := range []interface{}{records(nil).Len, nil} {
+		func() {
+			defer func() {
+				msg, _ := recover().(string)
+				if !strings.HasPrefix(msg, "sorts: Slice sorts need a slice") {
+					t.Errorf("SliceByUint64(%T): panicked with %q", notSlice, msg)
+				}
+			}()
+			SliceByUint64(notSlice, func(i int) uint64 { return 0 })
+		}()
+	}
+}
+
+func benchSlice(b *testing.B, sorter func([]record)) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		recs := randomRecords(1 << 16)
+		b.StartTimer()
+		sorter(recs)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSliceByUint64(b *testing.B) {
+	benchSlice(b, func(recs []record) {
+		SliceByUint64(recs, func(i int) uint64 { return recs[i].id })
+	})
+}
+
+func BenchmarkInterfaceByUint64(b *testing.B) {
+	benchSlice(b, func(recs []record) { ByUint64(records(recs)) })
+}
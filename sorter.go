@@ -0,0 +1,75 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// A Sorter radix sorts like the package-level By* functions, but keeps
+// the state that serial sorts set up on every call, so sorting many small
+// collections in a loop doesn't allocate.  (Each level's bucket tables
+// live on the stack either way; it's the bookkeeping around them that
+// a Sorter saves.)  Sorts big enough to run in parallel are handed to the
+// same parallel code the By* functions use.  The zero value is ready to
+// use, as is the result of NewSorter.  A Sorter is not safe for
+// concurrent use: give each goroutine its own.
+type Sorter struct {
+	data   sort.Interface
+	sorter sortFunc
+	step   func(task)
+	run    sortRunner
+}
+
+// NewSorter returns a new Sorter.
+func NewSorter() *Sorter {
+	s := new(Sorter)
+	s.init()
+	return s
+}
+
+// init builds the funcs s hands out, once, so later sorts don't allocate
+// them.
+func (s *Sorter) init() {
+	if s.run != nil {
+		return
+	}
+	s.step = func(t task) { s.sorter(s.data, t, s.step) }
+	s.run = s.sort
+}
+
+// sort is a sortRunner that runs serial sorts through s.step.
+func (s *Sorter) sort(data sort.Interface, sorter sortFunc, initialTask task) {
+	if initialTask.cfg.workers(data.Len()) > 1 {
+		parallelSort(data, sorter, initialTask)
+		return
+	}
+	s.data, s.sorter = data, sorter
+	s.step(initialTask)
+	s.data, s.sorter = nil, nil
+}
+
+// ByUint64 sorts data like the package-level ByUint64.
+func (s *Sorter) ByUint64(data Uint64Interface) {
+	s.init()
+	mustSort(byUint64(data, s.run, VerifyResults, nil))
+}
+
+// ByInt64 sorts data like the package-level ByInt64.
+func (s *Sorter) ByInt64(data Int64Interface) {
+	s.init()
+	mustSort(byInt64(data, s.run, VerifyResults, nil))
+}
+
+// ByString sorts data like the package-level ByString.
+func (s *Sorter) ByString(data StringInterface) {
+	s.init()
+	mustSort(byString(data, s.run, VerifyResults, nil))
+}
+
+// ByBytes sorts data like the package-level ByBytes.
+func (s *Sorter) ByBytes(data BytesInterface) {
+	s.init()
+	mustSort(byBytes(data, s.run, VerifyResults, nil))
+}
@@ -0,0 +1,74 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSorter(t *testing.T) {
+	var zero Sorter
+	for _, s := range []*Sorter{NewSorter(), &zero} {
+		for _, n := range []int{10, 1000, 100000} {
+			u := make([]uint64, n)
+			for i := range u {
+				u[i] = uint64(rand.Int63())
+			}
+			s.ByUint64(Uint64Slice(u))
+			if !Uint64sAreSorted(u) {
+				t.Errorf("n=%d: ByUint64 didn't sort", n)
+			}
+			strs := make([]string, n)
+			for i := range strs {
+				strs[i] = randomWords(1)[0]
+			}
+			s.ByString(StringSlice(strs))
+			if !StringsAreSorted(strs) {
+				t.Errorf("n=%d: ByString didn't sort", n)
+			}
+		}
+	}
+
+	s := NewSorter()
+	var data Uint64Interface = make(Uint64Slice, 1000)
+	allocs := testing.AllocsPerRun(100, func() {
+		for i, u := 0, data.(Uint64Slice); i < len(u); i++ {
+			u[i] = uint64(i * 7919 % 1000)
+		}
+		s.ByUint64(data)
+	})
+	if allocs != 0 {
+		t.Errorf("reused Sorter made %g allocations per sort", allocs)
+	}
+}
+
+func benchSmallSorts(b *testing.B, sorter func(Uint64Interface)) {
+	b.StopTimer()
+	slices := make([]Uint64Interface, 100)
+	for i := range slices {
+		slices[i] = make(Uint64Slice, 1000)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, data := range slices {
+			for j, u := 0, data.(Uint64Slice); j < len(u); j++ {
+				u[j] = uint64(rand.Int63())
+			}
+		}
+		b.StartTimer()
+		for _, data := range slices {
+			sorter(data)
+		}
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSmallSortsByUint64(b *testing.B) { benchSmallSorts(b, ByUint64) }
+func BenchmarkSmallSortsSorter(b *testing.B)   { benchSmallSorts(b, NewSorter().ByUint64) }
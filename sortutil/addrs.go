@@ -0,0 +1,73 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"encoding/binary"
+	"net"
+	"net/netip"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// IPv4Key generates a uint64 key from an IPv4 address, in either of
+// net.IP's forms: the address as a 32-bit number, so address lists sort
+// numerically (10.0.0.2 before 10.0.0.10) rather than as strings.  IPs
+// that aren't IPv4 all get the key 1<<32, after every IPv4 address; use
+// AddrKey for lists mixing IPv4 and IPv6.
+func IPv4Key(ip net.IP) uint64 {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 1 << 32
+	}
+	return uint64(binary.BigEndian.Uint32(ip4))
+}
+
+// AddrKey generates a 128-bit key from a netip.Addr, as high and low
+// words for a sorts.Uint128Interface: the address's 16-byte form, with
+// IPv4 addresses as IPv4-mapped IPv6 ones (so 1.2.3.4 and ::ffff:1.2.3.4
+// get the same key), and zones ignored.  It fits sorts.SortUint128Func
+// as is, for sorting a []T by an address in it:
+//
+//	sorts.SortUint128Func(hosts, func(h Host) (hi, lo uint64) { return sortutil.AddrKey(h.Addr) })
+//
+// Addrs sorts a []netip.Addr in netip.Addr.Compare order instead.
+func AddrKey(a netip.Addr) (hi, lo uint64) {
+	b := a.As16()
+	return binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+}
+
+// addrs sorts a []netip.Addr by bit length, then its two address words.
+type addrs []netip.Addr
+
+func (p addrs) Len() int      { return len(p) }
+func (p addrs) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p addrs) Levels() int   { return 3 }
+func (p addrs) Key(i, level int) uint64 {
+	if level == 0 {
+		return uint64(p[i].BitLen())
+	}
+	hi, lo := AddrKey(p[i])
+	if level == 1 {
+		return hi
+	}
+	return lo
+}
+
+// Addrs sorts a slice of addresses in increasing netip.Addr.Compare
+// order: the zero Addr, then IPv4 addresses, then IPv6 ones, each
+// numerically, with addresses differing only in zone ordered by zone.  It
+// sorts by bit length and AddrKey as sorts.ByUint64s does, then orders
+// zones with an insertion sort, which costs one Compare per address when
+// there are no ties to reorder.
+func Addrs(a []netip.Addr) {
+	sorts.ByUint64s(addrs(a), nil)
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && a[j].Compare(a[j-1]) < 0; j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"net"
+	"net/netip"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestIPv4Key(t *testing.T) {
+	// in increasing order
+	ordered := []net.IP{
+		net.IPv4(0, 0, 0, 0),
+		net.IPv4(10, 0, 0, 2),
+		net.IP{10, 0, 0, 10},
+		net.IPv4(192, 168, 0, 1),
+		net.IPv4bcast,
+		net.ParseIP("::1"),
+	}
+	for i := 1; i < len(ordered); i++ {
+		if IPv4Key(ordered[i-1]) >= IPv4Key(ordered[i]) {
+			t.Errorf("IPv4Key(%v) >= IPv4Key(%v)", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestAddrKey(t *testing.T) {
+	hi, lo := AddrKey(netip.MustParseAddr("1.2.3.4"))
+	mhi, mlo := AddrKey(netip.MustParseAddr("::ffff:1.2.3.4"))
+	if hi != mhi || lo != mlo || hi != 0 || lo != 0xffff01020304 {
+		t.Errorf("AddrKey(1.2.3.4) = %x %x, AddrKey(::ffff:1.2.3.4) = %x %x", hi, lo, mhi, mlo)
+	}
+	hi, lo = AddrKey(netip.MustParseAddr("2001:db8::1"))
+	if hi != 0x20010db800000000 || lo != 1 {
+		t.Errorf("AddrKey(2001:db8::1) = %x %x", hi, lo)
+	}
+}
+
+func TestAddrs(t *testing.T) {
+	data := make([]netip.Addr, testSize)
+	for i := range data {
+		var b [16]byte
+		b[0], b[15] = byte(rand.Intn(3)), byte(rand.Intn(256))
+		switch rand.Intn(4) {
+		case 0:
+			data[i] = netip.AddrFrom4([4]byte{10, 0, b[0], b[15]})
+		case 1:
+			data[i] = netip.AddrFrom16(b)
+		case 2:
+			data[i] = netip.AddrFrom16(b).WithZone([]string{"eth0", "eth1"}[rand.Intn(2)])
+		}
+	}
+	Addrs(data)
+	if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i].Compare(data[j]) < 0 }) {
+		t.Errorf("didn't sort")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// bigInts co-sorts a []*big.Int and the byte keys derived from it.
+type bigInts struct {
+	x    []*big.Int
+	keys [][]byte
+}
+
+func (p bigInts) Len() int           { return len(p.x) }
+func (p bigInts) Less(i, j int) bool { return p.x[i].Cmp(p.x[j]) < 0 }
+func (p bigInts) Swap(i, j int) {
+	p.x[i], p.x[j] = p.x[j], p.x[i]
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+func (p bigInts) Key(i int) []byte { return p.keys[i] }
+
+// bigIntKeyBytes is how many leading bytes of each magnitude SortBigInts
+// keys on.
+const bigIntKeyBytes = 24
+
+// appendBigIntKey appends a key for v that sorts bytewise in v's numeric
+// order: a sign byte (negatives, then zero, then positives), the length
+// of the magnitude as 8 big-endian bytes, then the magnitude's first
+// bigIntKeyBytes bytes.  For negatives, the length and magnitude bytes
+// are complemented, so bigger magnitudes sort first.  Values whose
+// magnitudes are the same length and share those first bytes get equal
+// keys, and only Cmp can order them.
+func appendBigIntKey(key []byte, v *big.Int) []byte {
+	sign := v.Sign()
+	key = append(key, byte(sign+1))
+	if sign == 0 {
+		return key
+	}
+	start := len(key)
+	mag := v.Bytes()
+	key = binary.BigEndian.AppendUint64(key, uint64(len(mag)))
+	if len(mag) > bigIntKeyBytes {
+		mag = mag[:bigIntKeyBytes]
+	}
+	key = append(key, mag...)
+	if sign < 0 {
+		for i := start; i < len(key); i++ {
+			key[i] = ^key[i]
+		}
+	}
+	return key
+}
+
+// SortBigInts sorts x by numeric value, negatives first.  It derives one
+// byte key per value up front, from its sign, length, and first 24 bytes
+// (192 bits) of magnitude, so the radix passes never touch the big.Ints,
+// and key memory stays bounded however long the values are.  Values
+// tied on their keys, which only happens past 192 bits, are put in order
+// with Cmp, as ByBytes orders equal keys with Less; otherwise Cmp is used
+// only for small ranges and checking.  Elements must not be nil.
+func SortBigInts(x []*big.Int) {
+	size := 0
+	for _, v := range x {
+		n := (v.BitLen() + 7) / 8
+		if n > bigIntKeyBytes {
+			n = bigIntKeyBytes
+		}
+		size += 9 + n
+	}
+	buf := make([]byte, 0, size)
+	keys := make([][]byte, len(x))
+	for i, v := range x {
+		start := len(buf)
+		buf = appendBigIntKey(buf, v)
+		keys[i] = buf[start:len(buf):len(buf)]
+	}
+	sorts.ByBytes(bigInts{x, keys})
+}
@@ -0,0 +1,58 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/big"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSortBigInts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	x := make([]*big.Int, testSize)
+	for i := range x {
+		// mixed signs and bit lengths from 0 to about 200
+		v := new(big.Int).Rand(r, new(big.Int).Lsh(big.NewInt(1), uint(rand.Intn(200))))
+		if rand.Intn(2) == 0 {
+			v.Neg(v)
+		}
+		x[i] = v
+	}
+	x[0], x[1], x[2] = big.NewInt(0), big.NewInt(-1), big.NewInt(1)
+	x[3] = new(big.Int).Lsh(big.NewInt(-1), 300)
+
+	SortBigInts(x)
+	if !sort.SliceIsSorted(x, func(i, j int) bool { return x[i].Cmp(x[j]) < 0 }) {
+		t.Errorf("didn't sort")
+	}
+	if x[0].Cmp(new(big.Int).Lsh(big.NewInt(-1), 300)) != 0 {
+		t.Errorf("got %v first, want -2**300", x[0])
+	}
+}
+
+func TestSortBigIntsLong(t *testing.T) {
+	// values past the keys' 192 bits, many sharing all of them
+	big1000 := new(big.Int).Lsh(big.NewInt(1), 1000)
+	x := make([]*big.Int, testSize)
+	for i := range x {
+		v := new(big.Int).Add(big1000, big.NewInt(rand.Int63n(1000)-500))
+		if rand.Intn(4) == 0 {
+			v.SetBit(v, 990, 1) // same length, different first bytes
+		}
+		if rand.Intn(2) == 0 {
+			v.Neg(v)
+		}
+		x[i] = v
+	}
+	SortBigInts(x)
+	if !sort.SliceIsSorted(x, func(i, j int) bool { return x[i].Cmp(x[j]) < 0 }) {
+		t.Errorf("didn't sort")
+	}
+}
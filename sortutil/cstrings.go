@@ -0,0 +1,42 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"bytes"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// cStrings sorts offsets into a buffer of NUL-terminated strings by the
+// strings' contents.
+type cStrings struct {
+	buf     []byte
+	offsets []int
+}
+
+func (p cStrings) Len() int           { return len(p.offsets) }
+func (p cStrings) Less(i, j int) bool { return bytes.Compare(p.Key(i), p.Key(j)) < 0 }
+func (p cStrings) Swap(i, j int)      { p.offsets[i], p.offsets[j] = p.offsets[j], p.offsets[i] }
+
+// Key returns the string starting at offsets[i], up to but not including
+// the next NUL byte (or the end of buf if there isn't one).
+func (p cStrings) Key(i int) []byte {
+	s := p.buf[p.offsets[i]:]
+	if n := bytes.IndexByte(s, 0); n >= 0 {
+		s = s[:n]
+	}
+	return s
+}
+
+// SortCStrings sorts offsets, each the start of a NUL-terminated string in
+// buf (the layout C code often hands over), by the contents of the strings
+// they point to.  Only offsets is reordered; buf is untouched and no Go
+// strings are made.  A string running off the end of buf without a NUL
+// ends there.
+func SortCStrings(buf []byte, offsets []int) {
+	sorts.ByBytes(cStrings{buf, offsets})
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSortCStrings(t *testing.T) {
+	// "" appears twice; "tail" has no terminating NUL
+	buf := []byte("pear\x00\x00apple\x00fig\x00apples\x00\x00tail")
+	offsets := []int{0, 5, 6, 12, 16, 23, 24}
+	SortCStrings(buf, offsets)
+	want := []string{"", "", "apple", "apples", "fig", "pear", "tail"}
+	for i, off := range offsets {
+		s := buf[off:]
+		if n := bytes.IndexByte(s, 0); n >= 0 {
+			s = s[:n]
+		}
+		if string(s) != want[i] {
+			t.Fatalf("string %d is %q, want %q", i, s, want[i])
+		}
+	}
+
+	// lots of varying-length strings, enough to radix sort
+	var big []byte
+	offsets = offsets[:0]
+	for i := 0; i < testSize; i++ {
+		offsets = append(offsets, len(big))
+		big = strconv.AppendInt(big, rand.Int63n(1<<uint(rand.Intn(60))), 10)
+		big = append(big, 0)
+	}
+	SortCStrings(big, offsets)
+	prev := []byte(nil)
+	for _, off := range offsets {
+		s := big[off : off+bytes.IndexByte(big[off:], 0)]
+		if bytes.Compare(prev, s) > 0 {
+			t.Fatalf("%q sorted before %q", prev, s)
+		}
+		prev = s
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+// Descending keys: keys that an ascending sort puts in decreasing order
+// of the values they came from, for Key methods whose Less is written to
+// match, or for mixing descending fields into a wider key.  To sort a
+// whole collection largest first, sorts.ByUint64Descending and the like
+// are simpler.
+
+// DescIntKey generates a uint64 key from an int that sorts the ints in
+// decreasing order: the complement of IntKey.  A Less to go with it is
+// a[i] > a[j].
+func DescIntKey(i int) uint64 { return ^IntKey(i) }
+
+// DescInt64Key is DescIntKey for int64s.
+func DescInt64Key(i int64) uint64 { return ^Int64Key(i) }
+
+// DescUint64Key generates a uint64 key that sorts uint64s in decreasing
+// order: the complement of u.
+func DescUint64Key(u uint64) uint64 { return ^u }
+
+// DescFloat64Key generates a uint64 key that sorts float64s in exactly
+// the reverse of Float64Key's order, so NaNs like math.NaN()'s sort
+// first and +0 sorts just before -0.  A Less to go with it is
+// Float64Less(b, a).
+func DescFloat64Key(f float64) uint64 { return ^Float64Key(f) }
+
+// DescFloat32Key is DescFloat64Key for float32s.
+func DescFloat32Key(f float32) uint64 { return ^Float32Key(f) }
+
+// DescStringKey generates a []byte key that sorts strings in decreasing
+// byte order, for sorts.ByBytes.  Complementing the bytes alone would get
+// prefixes wrong, since "app" must sort after "apple", so the key also
+// ends in two 0xff bytes and any 0 byte in s becomes 0xff 0x00; the key
+// is then never a prefix of another, and at most twice s's length plus
+// 2.  sorts.ByStringDescending sorts by the strings themselves, without
+// building keys.
+func DescStringKey(s string) []byte {
+	key := make([]byte, 0, len(s)+2)
+	for i := 0; i < len(s); i++ {
+		if s[i] == 0 {
+			key = append(key, 0xff, 0)
+			continue
+		}
+		key = append(key, ^s[i])
+	}
+	return append(key, 0xff, 0xff)
+}
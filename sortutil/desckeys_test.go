@@ -0,0 +1,64 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestDescNumberKeys(t *testing.T) {
+	// in decreasing order
+	i64s := []int64{math.MaxInt64, 1, 0, -1, math.MinInt64}
+	for i := 1; i < len(i64s); i++ {
+		if DescInt64Key(i64s[i-1]) >= DescInt64Key(i64s[i]) {
+			t.Errorf("DescInt64Key(%d) >= DescInt64Key(%d)", i64s[i-1], i64s[i])
+		}
+		if DescIntKey(int(i64s[i-1])) >= DescIntKey(int(i64s[i])) {
+			t.Errorf("DescIntKey(%d) >= DescIntKey(%d)", i64s[i-1], i64s[i])
+		}
+	}
+	u64s := []uint64{math.MaxUint64, 1, 0}
+	for i := 1; i < len(u64s); i++ {
+		if DescUint64Key(u64s[i-1]) >= DescUint64Key(u64s[i]) {
+			t.Errorf("DescUint64Key(%d) >= DescUint64Key(%d)", u64s[i-1], u64s[i])
+		}
+	}
+	f64s := []float64{math.NaN(), math.Inf(1), 1, 0, math.Copysign(0, -1), -1, math.Inf(-1)}
+	for i := 1; i < len(f64s); i++ {
+		if DescFloat64Key(f64s[i-1]) >= DescFloat64Key(f64s[i]) {
+			t.Errorf("DescFloat64Key(%g) >= DescFloat64Key(%g)", f64s[i-1], f64s[i])
+		}
+		f, g := float32(f64s[i-1]), float32(f64s[i])
+		if DescFloat32Key(f) >= DescFloat32Key(g) {
+			t.Errorf("DescFloat32Key(%g) >= DescFloat32Key(%g)", f, g)
+		}
+	}
+}
+
+func TestDescStringKey(t *testing.T) {
+	// prefixes, 0 bytes and 0xff bytes, in every pairing
+	pieces := []string{"", "\x00", "\xff", "a", "ap", "app", "apple"}
+	words := make([]string, 200)
+	for i := range words {
+		for j := rand.Intn(3); j > 0; j-- {
+			words[i] += pieces[rand.Intn(len(pieces))]
+		}
+	}
+	for _, a := range words {
+		for _, b := range words {
+			got := bytes.Compare(DescStringKey(a), DescStringKey(b))
+			want := -bytes.Compare([]byte(a), []byte(b))
+			if got != want {
+				t.Fatalf("%q vs. %q: keys compare %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "math"
+
+// NaNOrder says where SortFloat64s, SortFloat32s and FloatKeyPolicy put
+// NaNs.
+type NaNOrder int
+
+const (
+	// NaNsLast puts all NaNs after +Inf.
+	NaNsLast NaNOrder = iota
+	// NaNsFirst puts all NaNs before -Inf.
+	NaNsFirst
+	// NaNsReject panics on finding a NaN.
+	NaNsReject
+)
+
+const nanRejectMessage = "sortutil: NaN found with NaNsReject"
+
+// SortFloat64s sorts a in increasing order, with all NaNs, whatever
+// their sign bits and payloads, together at the end or the start as nans
+// says.  Other values sort as Float64s sorts them, by Float64Key: -Inf
+// first, +Inf last, -0 just before +0.  The NaNs are left in no
+// particular order.  Float64s itself places NaNs by their sign bits,
+// putting those with it clear last and those with it set first; use
+// SortFloat64s when NaNs may come from anywhere.  With
+// NaNsReject, it panics if a contains a NaN, before sorting anything.
+func SortFloat64s(a []float64, nans NaNOrder) {
+	if nans == NaNsReject {
+		for _, f := range a {
+			if f != f {
+				panic(nanRejectMessage)
+			}
+		}
+		Float64s(a)
+		return
+	}
+	// swap the NaNs (or everything else) to the front
+	n := 0
+	for i, f := range a {
+		if (f != f) == (nans == NaNsFirst) {
+			a[i], a[n] = a[n], a[i]
+			n++
+		}
+	}
+	if nans == NaNsFirst {
+		Float64s(a[n:])
+	} else {
+		Float64s(a[:n])
+	}
+}
+
+// SortFloat32s is SortFloat64s for float32s.
+func SortFloat32s(a []float32, nans NaNOrder) {
+	if nans == NaNsReject {
+		for _, f := range a {
+			if f != f {
+				panic(nanRejectMessage)
+			}
+		}
+		Float32s(a)
+		return
+	}
+	n := 0
+	for i, f := range a {
+		if (f != f) == (nans == NaNsFirst) {
+			a[i], a[n] = a[n], a[i]
+			n++
+		}
+	}
+	if nans == NaNsFirst {
+		Float32s(a[n:])
+	} else {
+		Float32s(a[:n])
+	}
+}
+
+// A FloatKeyPolicy makes float keys that order NaNs and zeros as it
+// says, for when Float64Key's order, which sorts NaNs by sign bit and
+// -0 below +0, isn't what comparing with < leads one to expect.  The
+// zero value puts all NaNs last, whatever their sign bits, and keeps -0
+// below +0.
+type FloatKeyPolicy struct {
+	// NaNs says where keys put NaNs, all with one key whatever their
+	// sign and payload.  With NaNsReject, making a NaN's key panics.
+	NaNs NaNOrder
+	// EqualZeros gives -0 the same key as +0, as -0 == 0 in Go.
+	EqualZeros bool
+}
+
+// Float64Key generates a uint64 key from a float64 under p.  Numbers get
+// Float64Key's order, but NaNs get key 0 with NaNsFirst and
+// math.MaxUint64 with NaNsLast, which no number's key is.
+func (p FloatKeyPolicy) Float64Key(f float64) uint64 {
+	if f != f {
+		return p.nanKey()
+	}
+	if f == 0 && p.EqualZeros {
+		f = 0
+	}
+	return Float64Key(f)
+}
+
+// Float32Key is Float64Key for float32s.
+func (p FloatKeyPolicy) Float32Key(f float32) uint64 {
+	if f != f {
+		return p.nanKey()
+	}
+	if f == 0 && p.EqualZeros {
+		f = 0
+	}
+	return Float32Key(f)
+}
+
+// nanKey is every NaN's key under p.
+func (p FloatKeyPolicy) nanKey() uint64 {
+	switch p.NaNs {
+	case NaNsFirst:
+		return 0
+	case NaNsReject:
+		panic(nanRejectMessage)
+	}
+	return math.MaxUint64
+}
+
+// Float64Less compares float64s in p.Float64Key order, for a Less
+// consistent with those keys.
+func (p FloatKeyPolicy) Float64Less(f, g float64) bool {
+	return p.Float64Key(f) < p.Float64Key(g)
+}
+
+// Float32Less compares float32s in p.Float32Key order.
+func (p FloatKeyPolicy) Float32Less(f, g float32) bool {
+	return p.Float32Key(f) < p.Float32Key(g)
+}
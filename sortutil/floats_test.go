@@ -0,0 +1,110 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSortFloat64sNaNs(t *testing.T) {
+	negNaN := math.Copysign(math.NaN(), -1)
+	for _, nans := range []NaNOrder{NaNsLast, NaNsFirst} {
+		data := make([]float64, testSize)
+		for i := range data {
+			data[i] = rand.NormFloat64()
+		}
+		copy(data, []float64{math.NaN(), negNaN, math.Inf(1), math.Inf(-1), 0, math.Copysign(0, -1), negNaN})
+		rand.Shuffle(len(data), Float64Slice(data).Swap)
+		SortFloat64s(data, nans)
+
+		numbers, nanRun := data[:len(data)-3], data[len(data)-3:]
+		if nans == NaNsFirst {
+			numbers, nanRun = data[3:], data[:3]
+		}
+		for _, f := range nanRun {
+			if !math.IsNaN(f) {
+				t.Fatalf("nans=%d: %v among the NaNs", nans, f)
+			}
+		}
+		if !Float64sAreSorted(numbers) || !math.IsInf(numbers[0], -1) || !math.IsInf(numbers[len(numbers)-1], 1) {
+			t.Errorf("nans=%d: numbers out of order", nans)
+		}
+
+		data32 := make([]float32, len(data))
+		for i, f := range data {
+			data32[i] = float32(f)
+		}
+		rand.Shuffle(len(data32), Float32Slice(data32).Swap)
+		SortFloat32s(data32, nans)
+		nanAt := len(data32) - 3
+		if nans == NaNsFirst {
+			nanAt = 0
+		}
+		for i, f := range data32 {
+			if isNaN := f != f; isNaN != (i >= nanAt && i < nanAt+3) {
+				t.Fatalf("nans=%d: float32 %v at %d", nans, f, i)
+			}
+		}
+	}
+}
+
+func TestSortFloat64sReject(t *testing.T) {
+	data := []float64{3, 1, 2}
+	SortFloat64s(data, NaNsReject)
+	if !Float64sAreSorted(data) {
+		t.Errorf("didn't sort %v", data)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic on NaN")
+		}
+	}()
+	SortFloat32s([]float32{1, float32(math.NaN())}, NaNsReject)
+}
+
+func TestFloatKeyPolicy(t *testing.T) {
+	negNaN := math.Copysign(math.NaN(), -1)
+	negZero := math.Copysign(0, -1)
+	numbers := []float64{math.Inf(-1), -math.MaxFloat64, -1, negZero, 0, math.SmallestNonzeroFloat64, math.MaxFloat64, math.Inf(1)}
+	for _, p := range []FloatKeyPolicy{{NaNsLast, false}, {NaNsFirst, false}, {NaNsLast, true}, {NaNsFirst, true}} {
+		// in increasing key order, NaNs of both signs tied at one end
+		keys := []uint64{}
+		if p.NaNs == NaNsFirst {
+			keys = append(keys, p.Float64Key(negNaN), p.Float64Key(math.NaN()))
+		}
+		for _, f := range numbers {
+			keys = append(keys, p.Float64Key(f))
+		}
+		if p.NaNs == NaNsLast {
+			keys = append(keys, p.Float64Key(negNaN), p.Float64Key(math.NaN()))
+		}
+		for i := 1; i < len(keys); i++ {
+			tied := keys[i-1] == keys[i]
+			nanPair := p.NaNs == NaNsFirst && i == 1 || p.NaNs == NaNsLast && i == len(keys)-1
+			zeroPair := p.EqualZeros && keys[i] == p.Float64Key(0) && keys[i-1] == p.Float64Key(negZero)
+			if keys[i-1] > keys[i] || tied != (nanPair || zeroPair) {
+				t.Errorf("%+v: key %d is %x, key %d is %x", p, i-1, keys[i-1], i, keys[i])
+			}
+		}
+		if p.Float32Key(float32(negZero)) == p.Float32Key(0) != p.EqualZeros {
+			t.Errorf("%+v: float32 zeros tied is %v", p, !p.EqualZeros)
+		}
+		if p.Float32Less(float32(math.NaN()), 1) != (p.NaNs == NaNsFirst) {
+			t.Errorf("%+v: float32 NaN misplaced", p)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic on NaN")
+		}
+	}()
+	FloatKeyPolicy{NaNs: NaNsReject}.Float64Key(math.NaN())
+}
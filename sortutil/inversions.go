@@ -0,0 +1,52 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+// InversionCountUint64 sorts x in increasing order and returns how many
+// inversions it held beforehand: pairs i < j with x[i] > x[j].  Equal
+// values don't count.  0 means x was already sorted, and len(x)*(len(x)-1)/2
+// means it was strictly decreasing, the basis of measures like Kendall's
+// tau.
+//
+// Radix sorting moves items without comparing them pairwise, so there is
+// nothing to count along the way; this is a separate bottom-up merge sort,
+// O(n log n) with a len(x) scratch buffer, that counts as it merges.
+func InversionCountUint64(x []uint64) int {
+	n := len(x)
+	src, dst := x, make([]uint64, n)
+	inversions := 0
+	for width := 1; width < n; width *= 2 {
+		for a := 0; a < n; a += 2 * width {
+			m, b := a+width, a+2*width
+			if m > n {
+				m = n
+			}
+			if b > n {
+				b = n
+			}
+			i, j, k := a, m, a
+			for i < m && j < b {
+				if src[j] < src[i] {
+					// src[j] comes before everything left in src[i:m]
+					inversions += m - i
+					dst[k] = src[j]
+					j++
+				} else {
+					dst[k] = src[i]
+					i++
+				}
+				k++
+			}
+			k += copy(dst[k:], src[i:m])
+			copy(dst[k:], src[j:b])
+		}
+		src, dst = dst, src
+	}
+	if n > 0 && &src[0] != &x[0] {
+		copy(x, src)
+	}
+	return inversions
+}
@@ -0,0 +1,41 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestInversionCountUint64(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 7, 100, 1000} {
+		x := make([]uint64, n)
+		for i := range x {
+			x[i] = uint64(rand.Intn(n/2 + 1)) // some ties
+		}
+		want := 0
+		for i := range x {
+			for j := i + 1; j < n; j++ {
+				if x[i] > x[j] {
+					want++
+				}
+			}
+		}
+		if got := InversionCountUint64(x); got != want {
+			t.Errorf("n=%d: got %d inversions, want %d", n, got, want)
+		}
+		if !Uint64sAreSorted(x) {
+			t.Errorf("n=%d: didn't sort", n)
+		}
+	}
+
+	desc := []uint64{5, 4, 3, 2, 1}
+	if got := InversionCountUint64(desc); got != 10 {
+		t.Errorf("got %d inversions for a reversed slice, want 10", got)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+const (
+	keyBuilderWidthMessage = "KeyBuilder: fields take more than 64 bits"
+	keyBuilderRangeMessage = "KeyBuilder: value doesn't fit in its field"
+)
+
+// A KeyBuilder packs several small fields into one uint64 key, so
+// sorting by the key sorts by the first field, then the second, and so
+// on, like sorting by a tuple.  Each field is added with its width in
+// bits, most significant first:
+//
+//	key := sortutil.KeyBuilder{}.Uint(category, 16).Uint(timestamp, 32).Int(tiebreak, 16).Key()
+//
+// Signed fields have their sign bit flipped within their width, as
+// Int64Key does for 64 bits, so negatives sort below positives.  A field
+// value that doesn't fit its width panics rather than spilling into the
+// field above it, as does a total width past 64 bits.  The zero value is
+// an empty key; KeyBuilders are values, so building one doesn't
+// allocate.
+type KeyBuilder struct {
+	key  uint64
+	bits uint
+}
+
+// add appends width bits holding v, which must already fit.
+func (b KeyBuilder) add(v uint64, width uint) KeyBuilder {
+	if width > 64-b.bits {
+		panic(keyBuilderWidthMessage)
+	}
+	if width == 64 {
+		return KeyBuilder{v, 64}
+	}
+	return KeyBuilder{b.key<<width | v, b.bits + width}
+}
+
+// Uint adds an unsigned field width bits wide holding v.
+func (b KeyBuilder) Uint(v uint64, width uint) KeyBuilder {
+	if width < 64 && v>>width != 0 {
+		panic(keyBuilderRangeMessage)
+	}
+	return b.add(v, width)
+}
+
+// Int adds a signed field width bits wide holding v, which must be within
+// the range of a width-bit two's complement integer.
+func (b KeyBuilder) Int(v int64, width uint) KeyBuilder {
+	if width == 0 || width < 64 && (v < -1<<(width-1) || v >= 1<<(width-1)) {
+		panic(keyBuilderRangeMessage)
+	}
+	u := uint64(v) ^ 1<<(width-1)
+	if width < 64 {
+		u &= 1<<width - 1
+	}
+	return b.add(u, width)
+}
+
+// Bool adds a one-bit field, with false sorting before true.
+func (b KeyBuilder) Bool(v bool) KeyBuilder {
+	if v {
+		return b.add(1, 1)
+	}
+	return b.add(0, 1)
+}
+
+// Key returns the packed key.  Fields fill the low bits, so keys built
+// from the same sequence of field widths compare correctly whatever the
+// total width.
+func (b KeyBuilder) Key() uint64 { return b.key }
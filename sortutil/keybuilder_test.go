@@ -0,0 +1,68 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// event is a record keyed by three fields, one signed.
+type event struct {
+	category uint16
+	time     uint32
+	delta    int16
+}
+
+func eventKey(e event) uint64 {
+	return KeyBuilder{}.Uint(uint64(e.category), 16).Uint(uint64(e.time), 32).Int(int64(e.delta), 16).Key()
+}
+
+func TestKeyBuilder(t *testing.T) {
+	events := make([]event, testSize)
+	for i := range events {
+		events[i] = event{uint16(rand.Intn(4)), uint32(rand.Intn(4)) << 30, int16(rand.Intn(1 << 16))}
+	}
+	events[0] = event{0, 0, math.MinInt16}
+	events[1] = event{math.MaxUint16, math.MaxUint32, math.MaxInt16}
+	for _, a := range events {
+		for _, b := range events[:50] {
+			less := a.category < b.category ||
+				a.category == b.category && (a.time < b.time || a.time == b.time && a.delta < b.delta)
+			if less != (eventKey(a) < eventKey(b)) {
+				t.Fatalf("%v < %v is %v, but keys %x and %x say otherwise", a, b, less, eventKey(a), eventKey(b))
+			}
+		}
+	}
+
+	if k := (KeyBuilder{}).Int(math.MinInt64, 64).Key(); k != 0 {
+		t.Errorf("64-bit Int(MinInt64) = %x, want 0", k)
+	}
+	if k := (KeyBuilder{}).Bool(true).Uint(math.MaxUint32, 32).Bool(false).Key(); k != 1<<33|math.MaxUint32<<1 {
+		t.Errorf("Bool/Uint key = %x", k)
+	}
+
+	for name, f := range map[string]func(){
+		"too wide":        func() { KeyBuilder{}.Uint(0, 40).Uint(0, 25) },
+		"unsigned range":  func() { KeyBuilder{}.Uint(256, 8) },
+		"signed range":    func() { KeyBuilder{}.Int(128, 8) },
+		"negative range":  func() { KeyBuilder{}.Int(-129, 8) },
+		"zero-width Int":  func() { KeyBuilder{}.Int(0, 0) },
+		"bool past width": func() { KeyBuilder{}.Uint(0, 64).Bool(true) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: no panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+// mergeRun is what's left of one input to a k-way merge, and where it
+// came in the list of inputs, used to break ties.
+type mergeRun struct {
+	rest []uint64
+	n    int
+}
+
+// mergeHeap is a binary min-heap of non-empty runs, ordered by each run's
+// next value, then by input position.
+type mergeHeap []mergeRun
+
+func (h mergeHeap) less(i, j int) bool {
+	a, b := h[i].rest[0], h[j].rest[0]
+	return a < b || a == b && h[i].n < h[j].n
+}
+
+// down restores the heap property by moving item i down.
+func (h mergeHeap) down(i int) {
+	for {
+		smallest := i
+		if l := 2*i + 1; l < len(h) && h.less(l, smallest) {
+			smallest = l
+		}
+		if r := 2*i + 2; r < len(h) && h.less(r, smallest) {
+			smallest = r
+		}
+		if smallest == i {
+			return
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+}
+
+// KWayMergeUint64 merges runs, each already sorted in increasing order,
+// into one new sorted slice.  Equal values come out in the order of the
+// runs holding them, so the result is deterministic.  Sorting doesn't help
+// here, since radix sort can't take advantage of the runs already being in
+// order; this uses a min-heap of the runs, O(n log k) for n values in k
+// runs.  Runs may be empty or of any length.
+func KWayMergeUint64(runs [][]uint64) []uint64 {
+	n := 0
+	for _, r := range runs {
+		n += len(r)
+	}
+	return KWayMergeUint64Into(make([]uint64, 0, n), runs)
+}
+
+// KWayMergeUint64Into is KWayMergeUint64, appending the merged values to
+// dst and returning the extended slice, so a big enough dst avoids
+// allocating the output.  (The merge still allocates a k-entry heap.)
+// dst must not overlap any of the runs.
+func KWayMergeUint64Into(dst []uint64, runs [][]uint64) []uint64 {
+	h := make(mergeHeap, 0, len(runs))
+	for n, r := range runs {
+		if len(r) > 0 {
+			h = append(h, mergeRun{r, n})
+		}
+	}
+	for i := len(h)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	for len(h) > 1 {
+		dst = append(dst, h[0].rest[0])
+		h[0].rest = h[0].rest[1:]
+		if len(h[0].rest) == 0 {
+			last := len(h) - 1
+			h[0] = h[last]
+			h = h[:last]
+		}
+		h.down(0)
+	}
+	if len(h) == 1 {
+		dst = append(dst, h[0].rest...)
+	}
+	return dst
+}
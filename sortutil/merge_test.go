@@ -0,0 +1,52 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestKWayMergeUint64(t *testing.T) {
+	for _, k := range []int{0, 1, 2, 5, 64} {
+		runs := make([][]uint64, k)
+		var all []uint64
+		for i := range runs {
+			n := 0
+			if rand.Intn(4) != 0 { // leave some runs empty
+				n = rand.Intn(testSize)
+			}
+			runs[i] = make([]uint64, n)
+			for j := range runs[i] {
+				runs[i][j] = uint64(rand.Intn(testSize)) // some ties
+			}
+			Uint64s(runs[i])
+			all = append(all, runs[i]...)
+		}
+		Uint64s(all)
+
+		for _, got := range [][]uint64{
+			KWayMergeUint64(runs),
+			KWayMergeUint64Into(make([]uint64, 0, len(all)), runs),
+		} {
+			if len(got) != len(all) {
+				t.Fatalf("k=%d: got %d values, want %d", k, len(got), len(all))
+			}
+			for i := range all {
+				if got[i] != all[i] {
+					t.Fatalf("k=%d: got[%d]=%d, want %d", k, i, got[i], all[i])
+				}
+			}
+		}
+	}
+
+	dst := []uint64{7}
+	if got := KWayMergeUint64Into(dst, [][]uint64{{3, 4}, {1, 5}}); len(got) != 5 || got[0] != 7 || got[1] != 1 {
+		t.Errorf("KWayMergeUint64Into didn't append to dst: %v", got)
+	}
+}
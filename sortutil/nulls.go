@@ -0,0 +1,59 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+// Keys for nullable values, like database/sql's NullInt64.  A 64-bit
+// value uses all of a uint64 key, leaving no key for null that doesn't
+// tie with some value, so the 64-bit helpers return 128-bit keys, for a
+// sorts.Uint128Interface or sorts.SortUint128Func:
+//
+//	sorts.SortUint128Func(rows, func(r Row) (hi, lo uint64) {
+//		return sortutil.NullInt64Key(r.Age.Valid, r.Age.Int64, false)
+//	})
+//
+// The high word only says whether the value is null and which end nulls
+// go to, so ByUint128 sorts by it in one pass and the low words hold the
+// values' ordinary keys.
+
+// nullWord returns the high word of a nullable value's key.
+func nullWord(valid, nullsFirst bool) uint64 {
+	switch {
+	case valid:
+		return 1
+	case nullsFirst:
+		return 0
+	}
+	return 2
+}
+
+// NullInt64Key generates a 128-bit key from a nullable int64: valid
+// values are ordered like Int64Key's, and nulls all sort before them if
+// nullsFirst is set and after them if not.  v is ignored for nulls.
+func NullInt64Key(valid bool, v int64, nullsFirst bool) (hi, lo uint64) {
+	if !valid {
+		return nullWord(valid, nullsFirst), 0
+	}
+	return nullWord(valid, nullsFirst), Int64Key(v)
+}
+
+// NullFloat64Key is NullInt64Key for a nullable float64, with valid
+// values ordered like Float64Key's, NaNs included.
+func NullFloat64Key(valid bool, f float64, nullsFirst bool) (hi, lo uint64) {
+	if !valid {
+		return nullWord(valid, nullsFirst), 0
+	}
+	return nullWord(valid, nullsFirst), Float64Key(f)
+}
+
+// NullInt32Key generates a uint64 key from a nullable int32, ordered as
+// NullInt64Key orders: a 32-bit value leaves room in one word to put
+// nulls at either end.
+func NullInt32Key(valid bool, v int32, nullsFirst bool) uint64 {
+	if !valid {
+		return nullWord(valid, nullsFirst) << 32
+	}
+	return 1<<32 | uint64(uint32(v)^1<<31)
+}
@@ -0,0 +1,50 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// nullable is a value that may be null.
+type nullable struct {
+	valid bool
+	v     int64
+}
+
+func TestNullKeys(t *testing.T) {
+	null := nullable{}
+	values := []nullable{{true, math.MinInt64}, {true, -1}, {true, 0}, {true, math.MaxInt32}, {true, math.MaxInt64}}
+	less := func(a, b [2]uint64) bool { return a[0] < b[0] || a[0] == b[0] && a[1] < b[1] }
+	for _, nullsFirst := range []bool{true, false} {
+		// in increasing order
+		ordered := append([]nullable{null}, values...)
+		if !nullsFirst {
+			ordered = append(values, null)
+		}
+		for i := 1; i < len(ordered); i++ {
+			a, b := ordered[i-1], ordered[i]
+			var ka, kb [2]uint64
+			ka[0], ka[1] = NullInt64Key(a.valid, a.v, nullsFirst)
+			kb[0], kb[1] = NullInt64Key(b.valid, b.v, nullsFirst)
+			if !less(ka, kb) {
+				t.Errorf("nullsFirst=%v: NullInt64Key(%v) >= NullInt64Key(%v)", nullsFirst, a, b)
+			}
+			ka[0], ka[1] = NullFloat64Key(a.valid, float64(a.v), nullsFirst)
+			kb[0], kb[1] = NullFloat64Key(b.valid, float64(b.v), nullsFirst)
+			if !less(ka, kb) {
+				t.Errorf("nullsFirst=%v: NullFloat64Key(%v) >= NullFloat64Key(%v)", nullsFirst, a, b)
+			}
+			if a.v >= math.MinInt32 && a.v <= math.MaxInt32 && b.v >= math.MinInt32 && b.v <= math.MaxInt32 &&
+				NullInt32Key(a.valid, int32(a.v), nullsFirst) >= NullInt32Key(b.valid, int32(b.v), nullsFirst) {
+				t.Errorf("nullsFirst=%v: NullInt32Key(%v) >= NullInt32Key(%v)", nullsFirst, a, b)
+			}
+		}
+	}
+}
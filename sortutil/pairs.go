@@ -0,0 +1,43 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "github.com/twotwotwo/sorts"
+
+// uint64Float64s co-sorts a []uint64 of keys and a parallel []float64 of
+// values, permuting both the same way.
+type uint64Float64s struct {
+	keys []uint64
+	vals []float64
+}
+
+func (p uint64Float64s) Len() int           { return len(p.keys) }
+func (p uint64Float64s) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p uint64Float64s) Swap(i, j int) {
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+	p.vals[i], p.vals[j] = p.vals[j], p.vals[i]
+}
+func (p uint64Float64s) Key(i int) uint64 { return p.keys[i] }
+
+// SortThenPrefixSumUint64 sorts keys in increasing order, moving each
+// element of vals along with its key, and returns a new slice holding the
+// running sum of vals in sorted order: sums[i] is vals[0]+...+vals[i] after
+// sorting.  The sums at the end of each run of equal keys are exact group
+// totals; within a run, which value is added first is unspecified.  It
+// panics if keys and vals have different lengths.
+func SortThenPrefixSumUint64(keys []uint64, vals []float64) []float64 {
+	if len(keys) != len(vals) {
+		panic("SortThenPrefixSumUint64: keys and vals have different lengths")
+	}
+	sorts.ByUint64(uint64Float64s{keys, vals})
+	sums := make([]float64, len(vals))
+	total := 0.0
+	for i, v := range vals {
+		total += v
+		sums[i] = total
+	}
+	return sums
+}
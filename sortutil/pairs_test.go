@@ -0,0 +1,46 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSortThenPrefixSumUint64(t *testing.T) {
+	keys := make([]uint64, testSize)
+	vals := make([]float64, testSize)
+	for i := range keys {
+		keys[i] = uint64(rand.Intn(100))
+		vals[i] = float64(keys[i]) * 2 // so we can tell pairs stayed together
+	}
+
+	// reference: sort the keys by themselves and scan
+	want := append([]uint64(nil), keys...)
+	sort.Sort(Uint64Slice(want))
+
+	sums := SortThenPrefixSumUint64(keys, vals)
+	if !Uint64sAreSorted(keys) {
+		t.Fatalf("keys not sorted")
+	}
+	total := 0.0
+	for i := range keys {
+		if keys[i] != want[i] || vals[i] != float64(keys[i])*2 {
+			t.Fatalf("pair %d is (%d, %f), want (%d, %f)", i, keys[i], vals[i], want[i], float64(want[i])*2)
+		}
+		total += float64(want[i]) * 2
+		if sums[i] != total {
+			t.Fatalf("sums[%d] = %f, want %f", i, sums[i], total)
+		}
+	}
+
+	if len(SortThenPrefixSumUint64(nil, nil)) != 0 {
+		t.Errorf("expected no sums for no input")
+	}
+}
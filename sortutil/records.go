@@ -0,0 +1,47 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "github.com/twotwotwo/sorts"
+
+// records sorts a slice of structs by a key read from each one.
+type records[T any] struct {
+	recs []T
+	key  func(*T) uint64
+}
+
+func (p records[T]) Len() int           { return len(p.recs) }
+func (p records[T]) Less(i, j int) bool { return p.key(&p.recs[i]) < p.key(&p.recs[j]) }
+func (p records[T]) Swap(i, j int)      { p.recs[i], p.recs[j] = p.recs[j], p.recs[i] }
+func (p records[T]) Key(i int) uint64   { return p.key(&p.recs[i]) }
+
+// pointers sorts a slice of pointers to structs by a key read through each.
+type pointers[T any] struct {
+	ptrs []*T
+	key  func(*T) uint64
+}
+
+func (p pointers[T]) Len() int           { return len(p.ptrs) }
+func (p pointers[T]) Less(i, j int) bool { return p.key(p.ptrs[i]) < p.key(p.ptrs[j]) }
+func (p pointers[T]) Swap(i, j int)      { p.ptrs[i], p.ptrs[j] = p.ptrs[j], p.ptrs[i] }
+func (p pointers[T]) Key(i int) uint64   { return p.key(p.ptrs[i]) }
+
+// SortRecordsBy sorts a slice of structs (say, rows scanned from a
+// database) in increasing order of key, which is passed a pointer to each
+// record so reading a field doesn't copy it.  Sorting moves whole structs,
+// so when records are large, SortPointersByUint64 on a slice of pointers
+// to them can be much faster.  key should be cheap: it's called several
+// times per record.
+func SortRecordsBy[T any](recs []T, key func(*T) uint64) {
+	sorts.ByUint64(records[T]{recs, key})
+}
+
+// SortPointersByUint64 sorts a slice of pointers in increasing order of
+// key, moving only the pointers.  key is called with the pointers as they
+// are, so if ptrs may contain nil, key must handle it.
+func SortPointersByUint64[T any](ptrs []*T, key func(*T) uint64) {
+	sorts.ByUint64(pointers[T]{ptrs, key})
+}
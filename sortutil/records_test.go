@@ -0,0 +1,82 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// row is big enough that moving it costs noticeably more than moving a
+// pointer.
+type row struct {
+	ID      uint64
+	Payload [31]uint64
+}
+
+func rowID(r *row) uint64 { return r.ID }
+
+func randomRows(n int) []row {
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i].ID = uint64(rand.Intn(n))
+		rows[i].Payload[0] = rows[i].ID
+	}
+	return rows
+}
+
+func TestSortRecordsBy(t *testing.T) {
+	rows := randomRows(testSize)
+	SortRecordsBy(rows, rowID)
+	for i := range rows {
+		if rows[i].Payload[0] != rows[i].ID {
+			t.Fatalf("row %d got mangled", i)
+		}
+		if i > 0 && rows[i].ID < rows[i-1].ID {
+			t.Fatalf("rows not sorted at %d", i)
+		}
+	}
+}
+
+func TestSortPointersByUint64(t *testing.T) {
+	rows := randomRows(testSize)
+	ptrs := make([]*row, len(rows))
+	for i := range rows {
+		ptrs[i] = &rows[i]
+	}
+	SortPointersByUint64(ptrs, rowID)
+	for i := 1; i < len(ptrs); i++ {
+		if ptrs[i].ID < ptrs[i-1].ID {
+			t.Fatalf("pointers not sorted at %d", i)
+		}
+	}
+}
+
+func BenchmarkSortRecordsBy(b *testing.B) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		rows := randomRows(1 << 14)
+		b.StartTimer()
+		SortRecordsBy(rows, rowID)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortPointersByUint64(b *testing.B) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		rows := randomRows(1 << 14)
+		ptrs := make([]*row, len(rows))
+		for i := range rows {
+			ptrs[i] = &rows[i]
+		}
+		b.StartTimer()
+		SortPointersByUint64(ptrs, rowID)
+		b.StopTimer()
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// semver is a parsed semantic version; the numbers are digit strings,
+// without leading zeros.
+type semver struct {
+	major, minor, patch string
+	pre                 string
+	ok                  bool
+}
+
+// parseSemver parses v as "[v]MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD]",
+// with missing numbers taken as 0.
+func parseSemver(v string) (s semver) {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		v, s.pre = v[:i], v[i+1:]
+		if s.pre == "" {
+			return semver{}
+		}
+	}
+	nums := strings.SplitN(v, ".", 3)
+	for i, n := range nums {
+		if n == "" || strings.Trim(n, "0123456789") != "" {
+			return semver{}
+		}
+		n = strings.TrimLeft(n, "0")
+		switch i {
+		case 0:
+			s.major = n
+		case 1:
+			s.minor = n
+		case 2:
+			s.patch = n
+		}
+	}
+	s.ok = true
+	return s
+}
+
+// compareNumbers compares two digit strings without leading zeros by
+// numeric value.
+func compareNumbers(a, b string) int {
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// isNumber reports whether s is all digits.
+func isNumber(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// comparePrerelease compares prerelease tags in semver precedence: field
+// by field, numeric fields numerically and before others, other fields
+// by bytes, then fewer fields first.  A release (no tag) follows all
+// its prereleases.
+func comparePrerelease(a, b string) int {
+	if a == "" || b == "" {
+		return -strings.Compare(a, b)
+	}
+	fa, fb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(fa) && i < len(fb); i++ {
+		na, nb := isNumber(fa[i]), isNumber(fb[i])
+		var c int
+		switch {
+		case na && nb:
+			c = compareNumbers(strings.TrimLeft(fa[i], "0"), strings.TrimLeft(fb[i], "0"))
+		case na:
+			c = -1
+		case nb:
+			c = 1
+		default:
+			c = strings.Compare(fa[i], fb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(fa) < len(fb):
+		return -1
+	case len(fa) > len(fb):
+		return 1
+	}
+	return 0
+}
+
+// SemverLess reports whether version a sorts before version b: by
+// major, minor and patch number, then with prereleases ("1.0.0-rc.1")
+// before their release in semantic versioning's precedence, and exact
+// ties (differing only in build metadata or a "v" prefix) by string.
+// Versions are "[v]MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD]", missing
+// numbers counting as 0, and numbers can be any length.  Strings that
+// don't parse sort first, by string.
+func SemverLess(a, b string) bool {
+	sa, sb := parseSemver(a), parseSemver(b)
+	if sa.ok != sb.ok {
+		return sb.ok
+	}
+	if sa.ok {
+		for _, c := range []int{
+			compareNumbers(sa.major, sb.major),
+			compareNumbers(sa.minor, sb.minor),
+			compareNumbers(sa.patch, sb.patch),
+			comparePrerelease(sa.pre, sb.pre),
+		} {
+			if c != 0 {
+				return c < 0
+			}
+		}
+	}
+	return a < b
+}
+
+// semverField packs a version number into width bits, saturating.
+func semverField(n string, width uint) uint64 {
+	max := uint64(1)<<width - 1
+	v := uint64(0)
+	for i := 0; i < len(n); i++ {
+		v = v*10 + uint64(n[i]-'0')
+		if v >= max {
+			return max
+		}
+	}
+	return v
+}
+
+// SemverKey generates a uint64 key from a version string that sorts in
+// SemverLess order as far as it can: 20 bits each of major and minor
+// number, 23 of patch, and a low bit set for releases, so a release
+// sorts after its prereleases.  A number too big for its bits saturates
+// them and all the bits after it, so such versions tie; prereleases of
+// one version tie with each other, and strings that don't parse get key
+// 0, tying with 0.0.0 prereleases.  SemverLess breaks all those ties,
+// and Semvers uses it to do so.
+func SemverKey(v string) uint64 {
+	s := parseSemver(v)
+	if !s.ok {
+		return 0
+	}
+	major, minor, patch := semverField(s.major, 20), semverField(s.minor, 20), semverField(s.patch, 23)
+	k := major<<44 | minor<<24 | patch<<1
+	// a saturated field stands for many numbers, so the bits below it are
+	// saturated too, tying those versions for SemverLess to order
+	switch {
+	case major == 1<<20-1:
+		k |= 1<<44 - 1
+	case minor == 1<<20-1:
+		k |= 1<<24 - 1
+	case patch == 1<<23-1 || s.pre == "":
+		k |= 1
+	}
+	return k
+}
+
+// semverKeyed sorts version strings by their cached SemverKeys.
+type semverKeyed struct {
+	versions []string
+	keys     []uint64
+}
+
+func (p semverKeyed) Len() int           { return len(p.keys) }
+func (p semverKeyed) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p semverKeyed) Swap(i, j int) {
+	p.versions[i], p.versions[j] = p.versions[j], p.versions[i]
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+func (p semverKeyed) Key(i int) uint64 { return p.keys[i] }
+
+// Semvers sorts a slice of version strings in SemverLess order.  It
+// radix sorts by SemverKey, parsing each version once, and then sorts
+// each run of tied keys, such as the prereleases of one version, with
+// SemverLess.
+func Semvers(a []string) {
+	keys := make([]uint64, len(a))
+	for i, v := range a {
+		keys[i] = SemverKey(v)
+	}
+	sorts.ByUint64(semverKeyed{a, keys})
+	start := 0
+	for i := 1; i <= len(a); i++ {
+		if i < len(a) && keys[i] == keys[start] {
+			continue
+		}
+		if run := a[start:i]; len(run) > 1 {
+			sort.Slice(run, func(i, j int) bool { return SemverLess(run[i], run[j]) })
+		}
+		start = i
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSemverLess(t *testing.T) {
+	// in increasing order, from the semver spec's examples and edge cases
+	ordered := []string{
+		"", "garbage", "v", "0.0.0", "0.0.1", "0.9", "0.10.0",
+		"1.0.0-0", "1.0.0-2", "1.0.0-10", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta",
+		"1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1",
+		"1.0.0", "1.0.0+build.5", "v1.0.0", "1.0.1", "1.2", "1.10.0",
+		"1.1048575.7", "1.1048576.0", "1.1048576.3", "1.1048577.0", "2.0.0",
+		"1048575.5.0", "1048576.0.0", "99999999999999999999.0.0",
+	}
+	for i := 1; i < len(ordered); i++ {
+		a, b := ordered[i-1], ordered[i]
+		if !SemverLess(a, b) || SemverLess(b, a) {
+			t.Errorf("SemverLess(%q, %q) is wrong", a, b)
+		}
+		if SemverKey(a) > SemverKey(b) {
+			t.Errorf("SemverKey(%q) > SemverKey(%q)", a, b)
+		}
+	}
+
+	shuffled := append([]string(nil), ordered...)
+	rand.Shuffle(len(shuffled), sort.StringSlice(shuffled).Swap)
+	Semvers(shuffled)
+	if got, want := fmt.Sprintf("%q", shuffled), fmt.Sprintf("%q", ordered); got != want {
+		t.Errorf("Semvers: got %s, want %s", got, want)
+	}
+}
+
+func TestSemvers(t *testing.T) {
+	versions := make([]string, testSize)
+	pre := []string{"", "-alpha", "-alpha.1", "-rc.1", "-rc.2", "+build"}
+	for i := range versions {
+		versions[i] = fmt.Sprintf("%d.%d.%d%s", rand.Intn(3), rand.Intn(12), rand.Intn(12), pre[rand.Intn(len(pre))])
+	}
+	Semvers(versions)
+	if !sort.SliceIsSorted(versions, func(i, j int) bool { return SemverLess(versions[i], versions[j]) }) {
+		t.Errorf("didn't sort")
+	}
+}
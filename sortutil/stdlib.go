@@ -0,0 +1,27 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "sort"
+
+// Sort is a drop-in for sort.Sort that radix sorts the standard library's
+// slice types: sort.IntSlice, sort.StringSlice, and sort.Float64Slice go
+// to Ints, Strings, and SortFloat64s (with NaNsFirst, where Float64Slice's
+// Less puts them), and anything else goes to sort.Sort.  To use the
+// package's other sorts on one of those types, convert it to the
+// matching type here, e.g. IntSlice(p), which adds Key.
+func Sort(data sort.Interface) {
+	switch p := data.(type) {
+	case sort.IntSlice:
+		Ints(p)
+	case sort.StringSlice:
+		Strings(p)
+	case sort.Float64Slice:
+		SortFloat64s(p, NaNsFirst)
+	default:
+		sort.Sort(data)
+	}
+}
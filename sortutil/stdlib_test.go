@@ -0,0 +1,35 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestSort(t *testing.T) {
+	ints := make(sort.IntSlice, testSize)
+	strs := make(sort.StringSlice, testSize)
+	floats := make(sort.Float64Slice, testSize)
+	for i := range ints {
+		ints[i] = rand.Int() - rand.Int()
+		strs[i] = strconv.Itoa(ints[i])
+		floats[i] = rand.NormFloat64()
+	}
+	floats[0], floats[1] = math.NaN(), math.Inf(-1)
+
+	for _, data := range []sort.Interface{ints, strs, floats, sort.Reverse(ints)} {
+		Sort(data)
+		if !sort.IsSorted(data) {
+			t.Errorf("%T didn't sort", data)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"math"
+	"time"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// minTimeKey and maxTimeKey are the earliest and latest times TimeKey
+// orders exactly.
+var (
+	minTimeKey = time.Unix(0, math.MinInt64)
+	maxTimeKey = time.Unix(0, math.MaxInt64)
+)
+
+// TimeKey generates a uint64 key from a time.Time: its nanoseconds since
+// the Unix epoch, ordered like Int64Key's.  That's exact from late 1677
+// to early 2262, the range of t.UnixNano; earlier times all get key 0,
+// and later ones key math.MaxUint64.  Any monotonic clock reading is
+// ignored and keys follow the wall clock, as Before and After do when
+// either reading lacks one, so times compare the same whatever their
+// Location, and times from the same process sort by wall clock even
+// if it was stepped in between.  Times sorts without TimeKey's range
+// limit.
+func TimeKey(t time.Time) uint64 {
+	if t.Before(minTimeKey) {
+		return 0
+	}
+	if t.After(maxTimeKey) {
+		return math.MaxUint64
+	}
+	return Int64Key(t.UnixNano())
+}
+
+// DurationKey generates a uint64 key from a time.Duration, ordered like
+// Int64Key's, so negative durations sort first.  A plain []time.Duration
+// needs no key: sorts.Integers sorts it directly.
+func DurationKey(d time.Duration) uint64 { return Int64Key(int64(d)) }
+
+// times sorts a []time.Time by seconds, then nanoseconds, of wall clock.
+type times []time.Time
+
+func (p times) Len() int           { return len(p) }
+func (p times) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p times) KeyHi(i int) uint64 { return Int64Key(p[i].Unix()) }
+func (p times) KeyLo(i int) uint64 { return uint64(p[i].Nanosecond()) }
+
+// Times sorts a slice of times in increasing TimeKey order, wall clock
+// first to last, without TimeKey's range limit: it sorts by seconds and
+// then nanoseconds since the epoch as sorts.ByUint128 does, and the
+// seconds pass skips the bits all the times share, so times spanning
+// less than a few years cost about what uint64 keys do.
+func Times(a []time.Time) { sorts.ByUint128(times(a)) }
@@ -0,0 +1,76 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestTimeKey(t *testing.T) {
+	// in increasing order
+	tokyo := time.FixedZone("JST", 9*3600)
+	ordered := []time.Time{
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1677, 9, 21, 0, 12, 43, 145224192, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Unix(0, 0),
+		time.Date(2015, 6, 1, 9, 0, 0, 0, tokyo), // 00:00 UTC
+		time.Date(2015, 6, 1, 0, 0, 0, 1, time.UTC),
+		time.Date(2262, 4, 11, 23, 47, 16, 854775807, time.UTC),
+		time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for i := 1; i < len(ordered); i++ {
+		ki, kj := TimeKey(ordered[i-1]), TimeKey(ordered[i])
+		clamped := i == 1 || i == len(ordered)-1
+		if ki > kj || ki == kj && !clamped {
+			t.Errorf("TimeKey(%v) = %x, TimeKey(%v) = %x", ordered[i-1], ki, ordered[i], kj)
+		}
+	}
+	if k := TimeKey(ordered[0]); k != 0 {
+		t.Errorf("TimeKey(%v) = %x, want 0", ordered[0], k)
+	}
+	if k := TimeKey(ordered[len(ordered)-1]); k != math.MaxUint64 {
+		t.Errorf("TimeKey(%v) = %x, want max", ordered[len(ordered)-1], k)
+	}
+
+	// the monotonic reading doesn't change the key
+	now := time.Now()
+	if TimeKey(now) != TimeKey(now.Round(0)) {
+		t.Errorf("TimeKey depends on the monotonic clock reading")
+	}
+}
+
+func TestDurationKey(t *testing.T) {
+	// in increasing order
+	ordered := []time.Duration{math.MinInt64, -time.Hour, -1, 0, 1, time.Hour, math.MaxInt64}
+	for i := 1; i < len(ordered); i++ {
+		if DurationKey(ordered[i-1]) >= DurationKey(ordered[i]) {
+			t.Errorf("DurationKey(%v) >= DurationKey(%v)", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestTimes(t *testing.T) {
+	for _, span := range []time.Duration{time.Microsecond, time.Hour, 1e6 * time.Hour} {
+		data := make([]time.Time, testSize)
+		base := time.Now()
+		for i := range data {
+			data[i] = base.Add(time.Duration(rand.Int63n(int64(span))) - span/2)
+		}
+		data[0] = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+		data[1] = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+		Times(data)
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i].Before(data[j]) }) {
+			t.Errorf("span %v: didn't sort", span)
+		}
+	}
+}
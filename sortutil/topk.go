@@ -0,0 +1,53 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "math"
+
+// topKSlack is how many times k items TopKStreamUint64 buffers before
+// sorting and throwing away all but the k smallest.  Bigger means fewer,
+// larger sorts and more memory.
+const topKSlack = 4
+
+// TopKStreamUint64 reads ch until it's closed and returns the k smallest
+// values received, in increasing order (fewer if fewer than k arrived).
+// Memory stays proportional to k however long the stream runs: values
+// collect in a buffer of up to topKSlack*k, and whenever it fills it's radix
+// sorted and cut back to the k smallest.  After the first cut, values no
+// smaller than the current kth-smallest are dropped on arrival without
+// being buffered.  For the k largest, send ^v and complement the results.
+// If k <= 0, ch is still drained, and the result is nil.
+func TopKStreamUint64(ch <-chan uint64, k int) []uint64 {
+	if k <= 0 {
+		for range ch {
+		}
+		return nil
+	}
+	limit := math.MaxInt
+	if k <= limit/topKSlack {
+		limit = topKSlack * k
+	}
+	// grow buf as values arrive, so a big k on a short stream costs
+	// only what's sent
+	var buf []uint64
+	full, cutoff := false, uint64(0)
+	for v := range ch {
+		if full && v >= cutoff {
+			continue
+		}
+		buf = append(buf, v)
+		if len(buf) == limit {
+			Uint64s(buf)
+			buf = buf[:k]
+			full, cutoff = true, buf[k-1]
+		}
+	}
+	Uint64s(buf)
+	if len(buf) > k {
+		buf = buf[:k]
+	}
+	return buf
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestTopKStreamUint64(t *testing.T) {
+	for _, k := range []int{0, 1, 10, 1000, testSize * 2} {
+		data := make([]uint64, testSize)
+		for i := range data {
+			data[i] = uint64(rand.Intn(testSize)) // some repeats
+		}
+		ch := make(chan uint64)
+		go func() {
+			for _, v := range data {
+				ch <- v
+			}
+			close(ch)
+		}()
+		got := TopKStreamUint64(ch, k)
+
+		Uint64s(data)
+		want := data
+		if k < len(want) {
+			want = want[:k]
+		}
+		if k <= 0 {
+			want = nil
+		}
+		if len(got) != len(want) {
+			t.Fatalf("k=%d: got %d values, want %d", k, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("k=%d: got[%d]=%d, want %d", k, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestTopKStreamUint64HugeK(t *testing.T) {
+	for _, k := range []int{math.MaxInt / 3, math.MaxInt} {
+		ch := make(chan uint64, 3)
+		ch <- 3
+		ch <- 1
+		ch <- 2
+		close(ch)
+		got := TopKStreamUint64(ch, k)
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Errorf("k=%d: got %v, want [1 2 3]", k, got)
+		}
+	}
+}
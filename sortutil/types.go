@@ -11,6 +11,7 @@ package sortutil
 
 import (
 	"bytes"
+	"encoding/binary"
 	"math"
 	"sort"
 
@@ -18,29 +19,107 @@ import (
 )
 
 // Float32Key generates a uint64 key from a float32. Use with Float32Less.
+// Keys sort in numeric order, with -Inf and +Inf at the ends.  -0 and +0
+// get different keys, -0's directly below +0's, so they sort next to each
+// other.  NaNs sort by sign bit: NaNs with it clear (including
+// math.NaN()) sort after +Inf, and those with it set sort before -Inf.
+// Which sign arithmetic gives a NaN depends on the hardware (0/0 has the
+// bit set on amd64), so don't count on where those go; SortFloat32s puts
+// all NaNs at one end.  InvFloat32Key undoes the transform.
 func Float32Key(f float32) uint64 {
 	b := uint64(math.Float32bits(f)) << 32
 	b ^= ^(b>>63 - 1) | (1 << 63)
 	return b
 }
 
-// Float32Less compares float32s, treating NaN as greater than all numbers.
+// InvFloat32Key recovers the float32 a Float32Key came from.
+func InvFloat32Key(k uint64) float32 {
+	b := uint32(k >> 32)
+	if b>>31 == 1 {
+		b ^= 1 << 31
+	} else {
+		b = ^b
+	}
+	return math.Float32frombits(b)
+}
+
+// Float32Less compares float32s in Float32Key order, so NaNs with the
+// sign bit clear, like math.NaN()'s, are greater than all numbers, and
+// those with it set are less.
 func Float32Less(f, g float32) bool {
 	return Float32Key(f) < Float32Key(g)
 }
 
 // Float64Key generates a uint64 key from a float64. Use with Float64Less.
+// It orders -0, NaNs, and infinities the same way Float32Key does, and
+// InvFloat64Key undoes it.
 func Float64Key(f float64) uint64 {
 	b := math.Float64bits(f)
 	b ^= ^(b>>63 - 1) | (1 << 63)
 	return b
 }
 
-// Float64Less compares float64s, treating NaN as greater than all numbers.
+// InvFloat64Key recovers the float64 a Float64Key came from, NaN payload
+// and the sign of zero included.
+func InvFloat64Key(k uint64) float64 {
+	if k>>63 == 1 {
+		k ^= 1 << 63
+	} else {
+		k = ^k
+	}
+	return math.Float64frombits(k)
+}
+
+// Float64Less compares float64s in Float64Key order, ordering NaNs by
+// sign bit as Float32Less does.
 func Float64Less(f, g float64) bool {
 	return Float64Key(f) < Float64Key(g)
 }
 
+// Float64LEBytesToRadixKey turns the little-endian IEEE 754 float64 in
+// b[0:8] into an 8-byte key that sorts with bytes.Compare (and so ByBytes)
+// the way Float64Less sorts the numbers: it's Float64Key's bits, stored
+// big-endian, so -0 sorts just before 0, NaNs with the sign bit clear
+// sort after +Inf, and NaNs with it set sort before -Inf.  That lets
+// serialized float columns be sorted as bytes without decoding them, as
+// long as each is converted once up front.  It panics if b is shorter
+// than 8 bytes.
+func Float64LEBytesToRadixKey(b []byte) [8]byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], Float64Key(math.Float64frombits(binary.LittleEndian.Uint64(b))))
+	return k
+}
+
+// IntKey generates a uint64 key from an int for a Uint64Interface: it
+// flips the sign bit of the int's 64-bit two's complement form, so
+// negative ints get keys below all non-negative ones and keys sort in
+// the same order as the ints.  (An Int64Interface does this itself; the
+// Key functions are for types sorting by uint64 for other reasons, or
+// mixing an int into a wider key.)
+func IntKey(i int) uint64 { return Int64Key(int64(i)) }
+
+// Int32Key generates a uint64 key from an int32, ordered like IntKey's.
+func Int32Key(i int32) uint64 { return Int64Key(int64(i)) }
+
+// Int64Key generates a uint64 key from an int64, ordered like IntKey's:
+// math.MinInt64 maps to 0 and math.MaxInt64 to math.MaxUint64.
+func Int64Key(i int64) uint64 { return uint64(i) ^ 1<<63 }
+
+// Uint32Key generates a uint64 key from a uint32.  It's just the
+// conversion, here for completeness.
+func Uint32Key(u uint32) uint64 { return uint64(u) }
+
+// StringPrefixKey generates a uint64 key from the first 8 bytes of s,
+// big-endian, with shorter strings padded with zeros.  Keys sort as the
+// strings do except that strings sharing their first 8 bytes, or
+// differing only by trailing zero bytes, tie; a Less that breaks those
+// ties by the whole string stays consistent with the keys.
+// sorts.ByStringPrefixed sorts by these keys and then breaks the ties.
+func StringPrefixKey(s string) uint64 { return sorts.PrefixKey(s) }
+
+// BytesPrefixKey is StringPrefixKey for a []byte.
+func BytesPrefixKey(b []byte) uint64 { return sorts.PrefixKey(b) }
+
 // IntSlice attaches the methods of Int64Interface to []int, sorting in increasing order.
 type IntSlice []int
 
@@ -52,7 +131,7 @@ func (p IntSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p IntSlice) Key(i int) int64 { return int64(p[i]) }
 
 // Sort is a convenience method.
-func (p IntSlice) Sort() { sorts.ByInt64(p) }
+func (p IntSlice) Sort() { sorts.Integers(p) }
 
 // Int32Slice attaches the methods of Uint64Interface to []int32, sorting in increasing order.
 type Int32Slice []int32
@@ -65,7 +144,7 @@ func (p Int32Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p Int32Slice) Key(i int) int64 { return int64(p[i]) }
 
 // Sort is a convenience method.
-func (p Int32Slice) Sort() { sorts.ByInt64(p) }
+func (p Int32Slice) Sort() { sorts.Integers(p) }
 
 // Int64Slice attaches the methods of Uint64Interface to []int64, sorting in increasing order.
 type Int64Slice []int64
@@ -78,7 +157,7 @@ func (p Int64Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p Int64Slice) Key(i int) int64 { return p[i] }
 
 // Sort is a convenience method.
-func (p Int64Slice) Sort() { sorts.ByInt64(p) }
+func (p Int64Slice) Sort() { sorts.Integers(p) }
 
 // UintSlice attaches the methods of Uint64Interface to []uint, sorting in increasing order.
 type UintSlice []uint
@@ -104,7 +183,7 @@ func (p Uint32Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p Uint32Slice) Key(i int) uint64 { return uint64(p[i]) }
 
 // Sort is a convenience method.
-func (p Uint32Slice) Sort() { sorts.ByUint64(p) }
+func (p Uint32Slice) Sort() { sorts.Integers(p) }
 
 // Uint64Slice attaches the methods of Uint64Interface to []uint64, sorting in increasing order.
 type Uint64Slice []uint64
@@ -117,9 +196,21 @@ func (p Uint64Slice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p Uint64Slice) Key(i int) uint64 { return p[i] }
 
 // Sort is a convenience method.
-func (p Uint64Slice) Sort() { sorts.ByUint64(p) }
+func (p Uint64Slice) Sort() { sorts.Integers(p) }
+
+// uint64sDescending sorts a []uint64 in decreasing order.  Its Key is the
+// bitwise complement of each value, so the radix passes see an ascending
+// sort and run exactly as fast as they do for Uint64Slice.
+type uint64sDescending []uint64
 
-// Float32Slice attaches the methods of Uint64Interface to []uint32, sorting in increasing order, NaNs last.
+func (p uint64sDescending) Len() int           { return len(p) }
+func (p uint64sDescending) Less(i, j int) bool { return p[i] > p[j] }
+func (p uint64sDescending) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p uint64sDescending) Key(i int) uint64   { return ^p[i] }
+
+// Float32Slice attaches the methods of Uint64Interface to []float32,
+// sorting in increasing order, with NaNs placed by sign bit as Float32Key
+// places them.  SortFloat32s can put all NaNs at one end instead.
 type Float32Slice []float32
 
 func (p Float32Slice) Len() int           { return len(p) }
@@ -132,7 +223,9 @@ func (p Float32Slice) Key(i int) uint64 { return Float32Key(p[i]) }
 // Sort is a convenience method.
 func (p Float32Slice) Sort() { sorts.ByUint64(p) }
 
-// Float64Slice attaches the methods of Uint64Interface to []float64, sorting in increasing order, NaNs last.
+// Float64Slice attaches the methods of Uint64Interface to []float64,
+// sorting in increasing order, with NaNs placed by sign bit as Float64Key
+// places them.  SortFloat64s can put all NaNs at one end instead.
 type Float64Slice []float64
 
 func (p Float64Slice) Len() int           { return len(p) }
@@ -156,7 +249,7 @@ func (p StringSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p StringSlice) Key(i int) string { return p[i] }
 
 // Sort is a convenience method.
-func (p StringSlice) Sort() { sorts.ByString(p) }
+func (p StringSlice) Sort() { sorts.SortStrings(p) }
 
 // BytesSlice attaches the methods of BytesInterface to [][]byte, sorting in increasing order.
 type BytesSlice [][]byte
@@ -169,7 +262,7 @@ func (p BytesSlice) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p BytesSlice) Key(i int) []byte { return p[i] }
 
 // Sort is a convenience method.
-func (p BytesSlice) Sort() { sorts.ByBytes(p) }
+func (p BytesSlice) Sort() { sorts.SortByteSlices(p) }
 
 // Ints sorts a slice of ints in increasing order.
 func Ints(a []int) { IntSlice(a).Sort() }
@@ -183,23 +276,45 @@ func Int64s(a []int64) { Int64Slice(a).Sort() }
 // Uints sorts a slice of ints in increasing order.
 func Uints(a []uint) { UintSlice(a).Sort() }
 
-// Uint32s sorts a slice of uint64s in increasing order.
-func Uint32s(a []uint32) { Uint32Slice(a).Sort() }
-
 // Uint64s sorts a slice of uint64s in increasing order.
 func Uint64s(a []uint64) { Uint64Slice(a).Sort() }
 
-// Float32s sorts a slice of uint64s in increasing order, NaNs last.
+// SortUint64sDescending sorts a slice of uint64s in decreasing order, in
+// one pass rather than sorting and then reversing.
+func SortUint64sDescending(a []uint64) { sorts.ByUint64(uint64sDescending(a)) }
+
+// Float32s sorts a slice of float32s in increasing order, in Float32Key
+// order; SortFloat32s puts all NaNs at one end.
 func Float32s(a []float32) { Float32Slice(a).Sort() }
 
-// Float64s sorts a slice of uint64s in increasing order, NaNs last.
+// Float64s sorts a slice of float64s in increasing order, in Float64Key
+// order; SortFloat64s puts all NaNs at one end.
 func Float64s(a []float64) { Float64Slice(a).Sort() }
 
 // Strings sorts a slice of strings in increasing order.
-func Strings(a []string) { StringSlice(a).Sort() }
+func Strings(a []string) { sorts.SortStrings(a) }
 
 // Bytes sorts a slice of byte slices in increasing order.
-func Bytes(a [][]byte) { BytesSlice(a).Sort() }
+func Bytes(a [][]byte) { sorts.SortByteSlices(a) }
+
+// BuildSortedStringSet sorts a and returns its distinct strings in
+// increasing order.  Sorting puts duplicates next to each other, so they
+// are dropped in one pass with no map; the result reuses a's storage, and
+// a's contents past the result's length are unspecified.
+func BuildSortedStringSet(a []string) []string {
+	Strings(a)
+	if len(a) == 0 {
+		return a
+	}
+	n := 1
+	for _, s := range a[1:] {
+		if s != a[n-1] {
+			a[n] = s
+			n++
+		}
+	}
+	return a[:n]
+}
 
 // IntsAreSorted tests whether a slice of ints is sorted in increasing order.
 func IntsAreSorted(a []int) bool { return sort.IsSorted(IntSlice(a)) }
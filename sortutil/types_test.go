@@ -8,8 +8,12 @@
 package sortutil_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"github.com/twotwotwo/sorts"
 	. "github.com/twotwotwo/sorts/sortutil"
 	"math"
+	"math/rand"
 	"sort"
 	"testing"
 )
@@ -23,6 +27,133 @@ var uints = [...]uint{74, 59, 238, 784, 9845, 959, 905, 0, 0, 42, 7586, 5467984,
 var float64s = [...]float64{74.3, 59.0, math.Inf(1), 238.2, -784.0, 2.3, math.NaN(), math.NaN(), math.Inf(-1), 9845.768, -959.7485, -1e30, 1e30, 905, 7.8, 7.8}
 var strings = [...]string{"", "Hello", "foo", "bar", "foo", "f00", "%*&^*&^&", "***"}
 
+func TestFloatKeys(t *testing.T) {
+	negNaN := math.Copysign(math.NaN(), -1)
+	// in increasing key order
+	specials := []float64{negNaN, math.Inf(-1), -math.MaxFloat64, -1, -math.SmallestNonzeroFloat64, math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 1, math.MaxFloat64, math.Inf(1), math.NaN()}
+	for i := 1; i < len(specials); i++ {
+		if Float64Key(specials[i-1]) >= Float64Key(specials[i]) {
+			t.Errorf("Float64Key(%g) >= Float64Key(%g)", specials[i-1], specials[i])
+		}
+		f, g := float32(specials[i-1]), float32(specials[i])
+		if f != g && Float32Key(f) >= Float32Key(g) {
+			t.Errorf("Float32Key(%g) >= Float32Key(%g)", f, g)
+		}
+	}
+
+	vals := append([]float64(nil), specials...)
+	for i := 0; i < testSize; i++ {
+		vals = append(vals, rand.NormFloat64()*math.Pow(10, float64(rand.Intn(40)-20)))
+	}
+	for _, v := range vals {
+		if got := InvFloat64Key(Float64Key(v)); math.Float64bits(got) != math.Float64bits(v) {
+			t.Errorf("InvFloat64Key(Float64Key(%g)) = %g", v, got)
+		}
+		f := float32(v)
+		if got := InvFloat32Key(Float32Key(f)); math.Float32bits(got) != math.Float32bits(f) {
+			t.Errorf("InvFloat32Key(Float32Key(%g)) = %g", f, got)
+		}
+	}
+	for i := range vals {
+		for _, j := range []int{rand.Intn(len(vals)), rand.Intn(len(vals))} {
+			a, b := vals[i], vals[j]
+			if a < b && Float64Key(a) >= Float64Key(b) {
+				t.Fatalf("%g < %g but keys aren't", a, b)
+			}
+		}
+	}
+}
+
+// intKeyed sorts ints through IntKey, with a Less that must agree.
+type intKeyed []int
+
+func (p intKeyed) Len() int           { return len(p) }
+func (p intKeyed) Less(i, j int) bool { return p[i] < p[j] }
+func (p intKeyed) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p intKeyed) Key(i int) uint64   { return IntKey(p[i]) }
+
+func TestIntKeys(t *testing.T) {
+	const maxInt = int(^uint(0) >> 1)
+	data := make(intKeyed, testSize)
+	for i := range data {
+		data[i] = rand.Int() - maxInt/2
+	}
+	copy(data, []int{maxInt, 1, 0, -1, -maxInt - 1})
+	sorts.ByUint64(data)
+	if !sort.IsSorted(data) {
+		t.Errorf("IntKey sorted %v", data)
+	}
+	if data[0] != -maxInt-1 || data[len(data)-1] != maxInt {
+		t.Errorf("IntKey put extremes at %d and %d", data[0], data[len(data)-1])
+	}
+
+	i32s := []int32{math.MaxInt32, 1, 0, -1, math.MinInt32}
+	for i := 1; i < len(i32s); i++ {
+		if Int32Key(i32s[i]) >= Int32Key(i32s[i-1]) {
+			t.Errorf("Int32Key(%d) >= Int32Key(%d)", i32s[i], i32s[i-1])
+		}
+	}
+	i64s := []int64{math.MaxInt64, 1, 0, -1, math.MinInt64}
+	for i := 1; i < len(i64s); i++ {
+		if Int64Key(i64s[i]) >= Int64Key(i64s[i-1]) {
+			t.Errorf("Int64Key(%d) >= Int64Key(%d)", i64s[i], i64s[i-1])
+		}
+	}
+	if Int64Key(math.MinInt64) != 0 || Int64Key(math.MaxInt64) != math.MaxUint64 {
+		t.Error("Int64Key doesn't map the int64 range onto the uint64 range")
+	}
+	if Uint32Key(math.MaxUint32) != math.MaxUint32 {
+		t.Error("Uint32Key changed its input")
+	}
+}
+
+func TestStringPrefixKey(t *testing.T) {
+	// in increasing order, with ties marked
+	ordered := []struct {
+		s    string
+		tied bool // with the one before
+	}{{"", false}, {"\x00", true}, {"a", false}, {"ab", false}, {"abcdefgh", false}, {"abcdefghi", true}, {"b", false}, {"\xff", false}}
+	for i := 1; i < len(ordered); i++ {
+		a, b := ordered[i-1].s, ordered[i].s
+		ka, kb := StringPrefixKey(a), StringPrefixKey(b)
+		if ka > kb || (ka == kb) != ordered[i].tied {
+			t.Errorf("StringPrefixKey(%q) = %x, StringPrefixKey(%q) = %x", a, ka, b, kb)
+		}
+		if BytesPrefixKey([]byte(b)) != kb {
+			t.Errorf("BytesPrefixKey(%q) != StringPrefixKey", b)
+		}
+	}
+}
+
+func TestFloat64LEBytesToRadixKey(t *testing.T) {
+	// in increasing order, NaNs placed by sign bit
+	negNaN := math.Copysign(math.NaN(), -1)
+	vals := []float64{negNaN, math.Inf(-1), -math.MaxFloat64, -1, -math.SmallestNonzeroFloat64, math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 1, math.MaxFloat64, math.Inf(1), math.NaN()}
+	keys := make([][]byte, len(vals))
+	le := make([]byte, 8)
+	for i, v := range vals {
+		binary.LittleEndian.PutUint64(le, math.Float64bits(v))
+		k := Float64LEBytesToRadixKey(le)
+		keys[i] = k[:]
+	}
+	for i := 1; i < len(keys); i++ {
+		if bytes.Compare(keys[i-1], keys[i]) >= 0 {
+			t.Errorf("key for %g doesn't sort before key for %g", vals[i-1], vals[i])
+		}
+	}
+
+	shuffled := make([][]byte, len(keys))
+	for i, j := range rand.Perm(len(keys)) {
+		shuffled[i] = keys[j]
+	}
+	Bytes(shuffled)
+	for i := range keys {
+		if !bytes.Equal(shuffled[i], keys[i]) {
+			t.Fatalf("Bytes put %v at %d, want %v", shuffled[i], i, keys[i])
+		}
+	}
+}
+
 func TestSortIntSlice(t *testing.T) {
 	data := ints
 	a := make(IntSlice, testSize)
@@ -283,6 +414,19 @@ func TestUint64s(t *testing.T) {
 	}
 }
 
+func TestSortUint64sDescending(t *testing.T) {
+	data := make([]uint64, testSize)
+	for i := range data {
+		data[i] = uint64(uints[i%len(uints)])
+	}
+	data[0], data[1] = math.MaxUint64, 0
+	SortUint64sDescending(data)
+	if !sort.IsSorted(sort.Reverse(Uint64Slice(data))) {
+		t.Errorf("sorted %v", uints)
+		t.Errorf("   got %v", data)
+	}
+}
+
 func TestFloat32s(t *testing.T) {
 	data := make([]float32, len(float64s))
 	for i, v := range float64s {
@@ -316,6 +460,28 @@ func TestStrings(t *testing.T) {
 	}
 }
 
+func TestBuildSortedStringSet(t *testing.T) {
+	data := make([]string, testSize)
+	want := map[string]bool{}
+	for i := range data {
+		data[i] = strings[i%len(strings)]
+		want[data[i]] = true
+	}
+	set := BuildSortedStringSet(data)
+	if len(set) != len(want) {
+		t.Errorf("got %d strings, want %d", len(set), len(want))
+	}
+	for i, s := range set {
+		if !want[s] || i > 0 && set[i-1] >= s {
+			t.Errorf("bad set %v", set)
+			break
+		}
+	}
+	if len(BuildSortedStringSet(nil)) != 0 {
+		t.Errorf("expected empty set from no strings")
+	}
+}
+
 func TestBytes(t *testing.T) {
 	data := make([][]byte, len(strings))
 	for i, v := range strings {
@@ -327,3 +493,47 @@ func TestBytes(t *testing.T) {
 		t.Errorf("   got %v", data)
 	}
 }
+
+func benchUint64s(b *testing.B, sorter func([]uint64)) {
+	b.StopTimer()
+	data := make([]uint64, 1<<16)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = uint64(rand.Int63())
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkUint64s(b *testing.B) { benchUint64s(b, Uint64s) }
+
+func BenchmarkSortUint64sDescending(b *testing.B) { benchUint64s(b, SortUint64sDescending) }
+
+func BenchmarkStdlibUint64sDescending(b *testing.B) {
+	benchUint64s(b, func(a []uint64) { sort.Sort(sort.Reverse(Uint64Slice(a))) })
+}
+
+// benchStrings sorts 1e6 random strings of 5 to 20 letters.
+func benchStrings(b *testing.B, sorter func([]string)) {
+	b.StopTimer()
+	orig := make([]string, 1e6)
+	for i := range orig {
+		s := make([]byte, 5+rand.Intn(16))
+		for j := range s {
+			s[j] = byte('a' + rand.Intn(26))
+		}
+		orig[i] = string(s)
+	}
+	data := make([]string, len(orig))
+	for i := 0; i < b.N; i++ {
+		copy(data, orig)
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkStrings1e6(b *testing.B)       { benchStrings(b, Strings) }
+func BenchmarkStdlibStrings1e6(b *testing.B) { benchStrings(b, sort.Strings) }
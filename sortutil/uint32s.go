@@ -0,0 +1,55 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import "github.com/twotwotwo/sorts"
+
+// minUint32LSD is the shortest slice Uint32s sorts with its two 16-bit
+// passes; below it, clearing two 65536-entry count tables costs more than
+// the passes save, and sorts.Integers is faster.
+var minUint32LSD = 1 << 13
+
+// Uint32s sorts a slice of uint32s in increasing order.  Large slices are
+// sorted with two least-significant-digit radix passes over 16 bits each,
+// out of place: every element is copied to a buffer the size of a and
+// back again, each pass reading sequentially, rather than swapped in
+// place through up to four 8-bit passes.  That costs 4 bytes of memory
+// per element, and it runs on one goroutine; below 1<<13 elements,
+// sorts.Integers is used instead.
+func Uint32s(a []uint32) {
+	if len(a) < minUint32LSD {
+		sorts.Integers(a)
+		return
+	}
+
+	var lo, hi [1 << 16]int
+	for _, v := range a {
+		lo[v&0xffff]++
+		hi[v>>16]++
+	}
+	src, dst := a, make([]uint32, len(a))
+	for pass, counts := range [2]*[1 << 16]int{&lo, &hi} {
+		shift := uint(16 * pass)
+		if counts[(a[0]>>shift)&0xffff] == len(a) {
+			// every value has the same 16 bits here
+			continue
+		}
+		pos := 0
+		for b, n := range counts {
+			counts[b] = pos
+			pos += n
+		}
+		for _, v := range src {
+			b := (v >> shift) & 0xffff
+			dst[counts[b]] = v
+			counts[b]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &a[0] {
+		copy(a, src)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestUint32sLSD(t *testing.T) {
+	n := 1<<13 + 100
+	for _, m := range []uint32{0xffffffff, 0xffff, 0xffff0000, 0} {
+		data := make([]uint32, n)
+		for i := range data {
+			data[i] = rand.Uint32() & m
+		}
+		Uint32s(data)
+		if !Uint32sAreSorted(data) {
+			t.Errorf("mask %x: didn't sort", m)
+		}
+	}
+}
+
+func benchUint32s(b *testing.B, n int, sorter func([]uint32)) {
+	b.StopTimer()
+	data := make([]uint32, n)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = rand.Uint32()
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkUint32s1e4(b *testing.B)         { benchUint32s(b, 1e4, Uint32s) }
+func BenchmarkUint32s1e5(b *testing.B)         { benchUint32s(b, 1e5, Uint32s) }
+func BenchmarkUint32s1e6(b *testing.B)         { benchUint32s(b, 1e6, Uint32s) }
+func BenchmarkUint32sIntegers1e4(b *testing.B) { benchUint32s(b, 1e4, sorts.Integers[uint32]) }
+func BenchmarkUint32sIntegers1e5(b *testing.B) { benchUint32s(b, 1e5, sorts.Integers[uint32]) }
+func BenchmarkUint32sIntegers1e6(b *testing.B) { benchUint32s(b, 1e6, sorts.Integers[uint32]) }
@@ -0,0 +1,59 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil
+
+import (
+	"encoding/binary"
+
+	"github.com/twotwotwo/sorts"
+)
+
+// UUIDKey generates a 128-bit key from a UUID, as high and low words for
+// a sorts.Uint128Interface: the UUID's bytes, big-endian, so keys sort as
+// bytes.Compare sorts the raw UUIDs.  That's creation order for
+// version 6 and 7 UUIDs, which begin with their timestamps; see
+// UUIDTimeKey for version 1.
+func UUIDKey(u [16]byte) (hi, lo uint64) {
+	return binary.BigEndian.Uint64(u[:8]), binary.BigEndian.Uint64(u[8:])
+}
+
+// UUIDTimeKey generates a 128-bit key that orders version 1 UUIDs by
+// creation time.  A version 1 UUID stores its 60-bit timestamp low word
+// first, so its bytes don't sort by time; the high word here is the
+// timestamp reassembled, and the low word is the clock sequence and
+// node, in the UUID's byte order, to break ties.  The version bits are
+// dropped, so other versions get keys in no useful order.
+func UUIDTimeKey(u [16]byte) (hi, lo uint64) {
+	low := uint64(binary.BigEndian.Uint32(u[0:4]))
+	mid := uint64(binary.BigEndian.Uint16(u[4:6]))
+	high := uint64(binary.BigEndian.Uint16(u[6:8]) & 0x0fff)
+	return high<<48 | mid<<32 | low, binary.BigEndian.Uint64(u[8:])
+}
+
+// uuids sorts a [][16]byte by a 128-bit key func.
+type uuids struct {
+	u   [][16]byte
+	key func([16]byte) (hi, lo uint64)
+}
+
+func (p uuids) Len() int      { return len(p.u) }
+func (p uuids) Swap(i, j int) { p.u[i], p.u[j] = p.u[j], p.u[i] }
+func (p uuids) KeyHi(i int) uint64 {
+	hi, _ := p.key(p.u[i])
+	return hi
+}
+func (p uuids) KeyLo(i int) uint64 {
+	_, lo := p.key(p.u[i])
+	return lo
+}
+
+// UUIDs sorts a slice of UUIDs in increasing UUIDKey order, bytewise, as
+// sorts.ByUint128 sorts its keys.
+func UUIDs(a [][16]byte) { sorts.ByUint128(uuids{a, UUIDKey}) }
+
+// UUIDsByTime sorts a slice of version 1 UUIDs in increasing UUIDTimeKey
+// order, oldest first.
+func UUIDsByTime(a [][16]byte) { sorts.ByUint128(uuids{a, UUIDTimeKey}) }
@@ -0,0 +1,71 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sortutil_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// uuidV1 builds a version 1 UUID from a 60-bit timestamp and clock
+// sequence and node bits.
+func uuidV1(ts, rest uint64) (u [16]byte) {
+	binary.BigEndian.PutUint32(u[0:4], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:8], uint16(ts>>48)&0x0fff|0x1000)
+	binary.BigEndian.PutUint64(u[8:], rest)
+	return u
+}
+
+func TestUUIDs(t *testing.T) {
+	data := make([][16]byte, testSize)
+	for i := range data {
+		rand.Read(data[i][:])
+		data[i][0] &= 3 // some ties in the first word
+	}
+	UUIDs(data)
+	if !sort.SliceIsSorted(data, func(i, j int) bool { return bytes.Compare(data[i][:], data[j][:]) < 0 }) {
+		t.Errorf("UUIDs didn't sort")
+	}
+}
+
+func TestUUIDsByTime(t *testing.T) {
+	// b is older than a, but its bytes sort after a's
+	a, b := uuidV1(1<<48, 0), uuidV1(1<<31, 0)
+	if bytes.Compare(a[:], b[:]) > 0 {
+		t.Fatalf("test UUIDs already in time order bytewise")
+	}
+	if hi, _ := UUIDTimeKey(a); hi != 1<<48 {
+		t.Errorf("UUIDTimeKey timestamp = %x, want %x", hi, uint64(1<<48))
+	}
+
+	type entry struct{ ts, rest uint64 }
+	entries := make([]entry, testSize)
+	data := make([][16]byte, testSize)
+	for i := range entries {
+		entries[i] = entry{uint64(rand.Int63()) >> 3, uint64(rand.Intn(4))}
+		data[i] = uuidV1(entries[i].ts, entries[i].rest)
+	}
+	data[0], data[1] = a, b
+	entries[0], entries[1] = entry{1 << 48, 0}, entry{1 << 31, 0}
+	UUIDsByTime(data)
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ts != entries[j].ts {
+			return entries[i].ts < entries[j].ts
+		}
+		return entries[i].rest < entries[j].rest
+	})
+	for i, e := range entries {
+		if data[i] != uuidV1(e.ts, e.rest) {
+			t.Fatalf("item %d: got %x, want %x", i, data[i], uuidV1(e.ts, e.rest))
+		}
+	}
+}
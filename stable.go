@@ -0,0 +1,201 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"bytes"
+	"math/bits"
+	"sync"
+)
+
+// stableString sorts a permutation of data by key, then by original
+// index, which makes the order of equal keys the input order.
+type stableString struct {
+	data StringInterface
+	idx  []int
+}
+
+func (p stableString) Len() int { return len(p.idx) }
+func (p stableString) Less(i, j int) bool {
+	ki, kj := p.Key(i), p.Key(j)
+	if ki != kj {
+		return ki < kj
+	}
+	return p.idx[i] < p.idx[j]
+}
+func (p stableString) Swap(i, j int)    { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p stableString) Key(i int) string { return p.data.Key(p.idx[i]) }
+
+// stableBytes is stableString for BytesInterface.
+type stableBytes struct {
+	data BytesInterface
+	idx  []int
+}
+
+func (p stableBytes) Len() int { return len(p.idx) }
+func (p stableBytes) Less(i, j int) bool {
+	if c := bytes.Compare(p.Key(i), p.Key(j)); c != 0 {
+		return c < 0
+	}
+	return p.idx[i] < p.idx[j]
+}
+func (p stableBytes) Swap(i, j int)    { p.idx[i], p.idx[j] = p.idx[j], p.idx[i] }
+func (p stableBytes) Key(i int) []byte { return p.data.Key(p.idx[i]) }
+
+// permute moves data's items so the item at perm[i] ends up at i, using
+// only Swap, and leaves perm holding 0, 1, ..., n-1.
+func permute(data interface{ Swap(i, j int) }, perm []int) {
+	for i := range perm {
+		j := i
+		for perm[j] != i {
+			next := perm[j]
+			data.Swap(j, next)
+			perm[j] = j
+			j = next
+		}
+		perm[j] = j
+	}
+}
+
+// argsorterPool holds the index buffers of finished stable sorts for later
+// ones to reuse.
+var argsorterPool = sync.Pool{New: func() interface{} { return new(Argsorter) }}
+
+// ByStringStable sorts data by a string key, keeping items with equal keys
+// in their input order.  It radix sorts a permutation of data instead of
+// data itself, breaking ties by position, then moves data's items into
+// place with about one Swap each, so it costs O(n) extra memory (one int
+// per item, pooled between sorts) where ByString sorts in place.  The
+// radix passes are ByString's, read through the permutation, but each
+// run of equal keys is then quicksorted by position, so ties cost: in
+// BenchmarkByStringStable in stable_test.go, whose 64K keys take about a
+// hundred distinct values, it took about 4 times as long as ByString.
+// The order comes from the keys alone; data.Less is not called.
+func ByStringStable(data StringInterface) {
+	s := argsorterPool.Get().(*Argsorter)
+	s.byStringStable(data)
+	argsorterPool.Put(s)
+}
+
+// byStringStable is ByStringStable using s's index buffer.
+func (s *Argsorter) byStringStable(data StringInterface) {
+	idx := s.indices(data.Len())
+	ByString(stableString{data, idx})
+	permute(data, idx)
+}
+
+// ByBytesStable is ByStringStable for []byte keys, ordered as ByBytes
+// orders them.
+func ByBytesStable(data BytesInterface) {
+	s := argsorterPool.Get().(*Argsorter)
+	s.byBytesStable(data)
+	argsorterPool.Put(s)
+}
+
+// byBytesStable is ByBytesStable using s's index buffer.
+func (s *Argsorter) byBytesStable(data BytesInterface) {
+	idx := s.indices(data.Len())
+	ByBytes(stableBytes{data, idx})
+	permute(data, idx)
+}
+
+// A StableSorter does the stable sorts with scratch buffers it keeps and
+// reuses on every call.  ByUint64LSD, ByStringStable and the rest take
+// their buffers from pools, which the garbage collector may empty at any
+// time; a program sorting often at a steady size can keep a StableSorter
+// instead, and its sorts then stop allocating buffers after the first
+// call.  ByString and ByBytes still make a few small per-call
+// allocations, as do parallel passes.  The zero value is ready to use.
+// A StableSorter is not safe for concurrent use.
+type StableSorter struct {
+	lsd lsdBuffers
+	arg Argsorter
+}
+
+// ByUint64 is ByUint64LSD using s's buffers.
+func (s *StableSorter) ByUint64(data Uint64Interface) { s.lsd.sort(data) }
+
+// ByInt64 is ByInt64LSD using s's buffers.
+func (s *StableSorter) ByInt64(data Int64Interface) { s.lsd.sort(intwrapper{data}) }
+
+// ByString is ByStringStable using s's buffers.
+func (s *StableSorter) ByString(data StringInterface) { s.arg.byStringStable(data) }
+
+// ByBytes is ByBytesStable using s's buffers.
+func (s *StableSorter) ByBytes(data BytesInterface) { s.arg.byBytesStable(data) }
+
+// stableUint64 wraps a Uint64Interface, tagging each item with its
+// original index, which moves with the item, and implements
+// Uint128Interface, which ByUint128 sorts.  When the keys' range and the
+// indexes fit in 64 bits together, the high word packs them and the low
+// word is always 0, so ByUint128 never needs its second level;
+// otherwise the high word is the key and the low word the index.
+type stableUint64 struct {
+	data   Uint64Interface
+	idx    []int
+	min    uint64
+	shift  uint
+	packed bool
+}
+
+func (p *stableUint64) Len() int { return len(p.idx) }
+func (p *stableUint64) Swap(i, j int) {
+	p.data.Swap(i, j)
+	p.idx[i], p.idx[j] = p.idx[j], p.idx[i]
+}
+func (p *stableUint64) KeyHi(i int) uint64 {
+	if p.packed {
+		return (p.data.Key(i)-p.min)<<p.shift | uint64(p.idx[i])
+	}
+	return p.data.Key(i)
+}
+func (p *stableUint64) KeyLo(i int) uint64 {
+	if p.packed {
+		return 0
+	}
+	return uint64(p.idx[i])
+}
+
+// Stabilize wraps data so that sorting the result with ByUint128 sorts
+// data by key stably, keeping items with equal keys in their input
+// order, without a stable algorithm: each item's key is paired with its
+// original index, which becomes the tie-breaker.  If the keys' range
+// (from smallest to largest) and the indexes fit in 64 bits together,
+// as for 32-bit keys and up to 4G items, they're packed into one word
+// so ties cost nothing extra; otherwise the ties are sorted by index in
+// a second pass, as ByUint128 sorts its low words.
+//
+// Stabilize reads every key once to find their range and costs one int
+// per item; the index tags are only right for one sort, so wrap data
+// again to sort it again.  Unlike ByUint64LSD, it sorts in place.
+func Stabilize(data Uint64Interface) Uint128Interface {
+	l := data.Len()
+	p := &stableUint64{data: data, idx: make([]int, l)}
+	if l == 0 {
+		return p
+	}
+	min, max := data.Key(0), data.Key(0)
+	for i := range p.idx {
+		p.idx[i] = i
+		k := data.Key(i)
+		if k < min {
+			min = k
+		}
+		if k > max {
+			max = k
+		}
+	}
+	p.shift = uint(bits.Len(uint(l - 1)))
+	if bits.Len64(max-min)+int(p.shift) <= 64 {
+		p.min, p.packed = min, true
+	}
+	return p
+}
+
+// StabilizeInt64 is Stabilize for int64 keys.
+func StabilizeInt64(data Int64Interface) Uint128Interface {
+	return Stabilize(intwrapper{data})
+}
@@ -0,0 +1,191 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+)
+
+// taggedWords are strings tagged with their original position, keyed by
+// only their first two bytes so there are lots of equal keys.
+type taggedWords struct {
+	words []string
+	tags  []int
+}
+
+func (p taggedWords) Len() int           { return len(p.words) }
+func (p taggedWords) Less(i, j int) bool { return p.Key(i) < p.Key(j) }
+func (p taggedWords) Swap(i, j int) {
+	p.words[i], p.words[j] = p.words[j], p.words[i]
+	p.tags[i], p.tags[j] = p.tags[j], p.tags[i]
+}
+func (p taggedWords) Key(i int) string {
+	if len(p.words[i]) > 2 {
+		return p.words[i][:2]
+	}
+	return p.words[i]
+}
+
+// taggedBytes is taggedWords with []byte keys.
+type taggedBytes struct{ taggedWords }
+
+func (p taggedBytes) Key(i int) []byte { return []byte(p.taggedWords.Key(i)) }
+
+func checkStable(t *testing.T, name string, p taggedWords) {
+	for i := 1; i < p.Len(); i++ {
+		ki, kj := p.Key(i-1), p.Key(i)
+		if ki > kj || ki == kj && p.tags[i-1] > p.tags[i] {
+			t.Fatalf("%s: items %d and %d out of order: %q/%d, %q/%d", name, i-1, i, p.words[i-1], p.tags[i-1], p.words[i], p.tags[i])
+		}
+	}
+}
+
+func TestByStringStable(t *testing.T) {
+	varyQSortCutoff(func() {
+		p := taggedWords{randomWords(10000), make([]int, 10000)}
+		for i := range p.tags {
+			p.tags[i] = i
+		}
+		ByStringStable(p)
+		checkStable(t, "ByStringStable", p)
+
+		p = taggedWords{randomWords(10000), make([]int, 10000)}
+		for i := range p.tags {
+			p.tags[i] = i
+		}
+		ByBytesStable(taggedBytes{p})
+		checkStable(t, "ByBytesStable", p)
+	})
+}
+
+// indexEntries are the entries of a secondary index: keys that are often
+// duplicated, prefixes of each other, or empty, each with the row it was
+// inserted for.
+type indexEntries struct {
+	keys [][]byte
+	rows []int
+}
+
+func (p indexEntries) Len() int           { return len(p.keys) }
+func (p indexEntries) Less(i, j int) bool { return bytes.Compare(p.keys[i], p.keys[j]) < 0 }
+func (p indexEntries) Swap(i, j int) {
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+	p.rows[i], p.rows[j] = p.rows[j], p.rows[i]
+}
+func (p indexEntries) Key(i int) []byte { return p.keys[i] }
+
+func TestByBytesStableIndex(t *testing.T) {
+	values := [][]byte{nil, {}, {0}, {0, 0}, {'a'}, {'a', 0}, {'a', 'b'}, {0xff}}
+	varyQSortCutoff(func() {
+		p := indexEntries{make([][]byte, 10000), make([]int, 10000)}
+		for i := range p.keys {
+			p.keys[i] = values[rand.Intn(len(values))]
+			p.rows[i] = i
+		}
+		ByBytesStable(p)
+		for i := 1; i < p.Len(); i++ {
+			c := bytes.Compare(p.keys[i-1], p.keys[i])
+			if c > 0 || c == 0 && p.rows[i-1] > p.rows[i] {
+				t.Fatalf("entries %d and %d out of order: %q/%d, %q/%d", i-1, i, p.keys[i-1], p.rows[i-1], p.keys[i], p.rows[i])
+			}
+		}
+	})
+}
+
+func benchStable(b *testing.B, sorter func(StringInterface)) {
+	b.StopTimer()
+	for i := 0; i < b.N; i++ {
+		p := taggedWords{randomWords(1 << 16), make([]int, 1<<16)}
+		b.StartTimer()
+		sorter(p)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkByStringStable(b *testing.B)   { benchStable(b, ByStringStable) }
+func BenchmarkByStringUnstable(b *testing.B) { benchStable(b, ByString) }
+
+func TestStabilize(t *testing.T) {
+	for _, wide := range []bool{false, true} {
+		p := taggedUint64s{make([]uint64, 10000), make([]int, 10000)}
+		for i := range p.keys {
+			// few distinct keys; wide ones spanning all 64 bits can't be
+			// packed with the indexes
+			p.keys[i] = uint64(rand.Intn(8))
+			if wide {
+				p.keys[i] <<= 61
+			}
+			p.tags[i] = i
+		}
+		ByUint128(Stabilize(p))
+		for i := 1; i < p.Len(); i++ {
+			ki, kj := p.keys[i-1], p.keys[i]
+			if ki > kj || ki == kj && p.tags[i-1] > p.tags[i] {
+				t.Fatalf("wide=%v: items %d and %d out of order: %x/%d, %x/%d", wide, i-1, i, ki, p.tags[i-1], kj, p.tags[i])
+			}
+		}
+	}
+
+	q := taggedInt64s{taggedUint64s{make([]uint64, 10000), make([]int, 10000)}}
+	for i := range q.keys {
+		q.keys[i] = uint64(rand.Intn(16) - 8)
+		q.tags[i] = i
+	}
+	ByUint128(StabilizeInt64(q))
+	for i := 1; i < q.Len(); i++ {
+		ki, kj := q.Key(i-1), q.Key(i)
+		if ki > kj || ki == kj && q.tags[i-1] > q.tags[i] {
+			t.Fatalf("int64: items %d and %d out of order: %d/%d, %d/%d", i-1, i, ki, q.tags[i-1], kj, q.tags[i])
+		}
+	}
+}
+
+func TestStableSorter(t *testing.T) {
+	var s StableSorter
+	p := taggedUint64s{make([]uint64, 10000), make([]int, 10000)}
+	w := taggedWords{make([]string, 10000), make([]int, 10000)}
+	words := randomWords(len(w.words))
+	fill := func() {
+		for i := range p.keys {
+			p.keys[i], p.tags[i] = uint64(rand.Intn(16)), i
+		}
+		copy(w.words, words)
+		for i := range w.tags {
+			w.tags[i] = i
+		}
+	}
+	fill()
+	s.ByUint64(p)
+	s.ByString(w)
+	checkStable(t, "ByString", w)
+	for i := 1; i < p.Len(); i++ {
+		if p.keys[i-1] > p.keys[i] || p.keys[i-1] == p.keys[i] && p.tags[i-1] > p.tags[i] {
+			t.Fatalf("ByUint64: items %d and %d out of order", i-1, i)
+		}
+	}
+
+	// warmed up, the serial sorts allocate nothing in proportion to the
+	// data; ByString's passes still allocate a few small things per call
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	if n := testing.AllocsPerRun(10, func() { fill(); s.ByUint64(&p) }); n > 0 {
+		t.Errorf("ByUint64 allocated %v times per sort", n)
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < 10; i++ {
+		fill()
+		s.ByString(&w)
+	}
+	runtime.ReadMemStats(&after)
+	if n := (after.TotalAlloc - before.TotalAlloc) / 10; n > 1024 {
+		t.Errorf("ByString allocated %d bytes per sort", n)
+	}
+}
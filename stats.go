@@ -0,0 +1,76 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Stats counts what radix sorts run with Options.Stats did.  Each sort
+// adds to the counts, so zero it between sorts to see them one at a
+// time.  The fields are updated atomically while a sort runs; read them
+// after it returns.  Sorts too small to radix sort at all aren't counted.
+type Stats struct {
+	// RadixPasses is how many ranges got a radix counting pass.
+	RadixPasses int64
+	// PrefixSkips is how many of those passes found nothing to split on
+	// at the bits or byte they looked at, so the range was passed on,
+	// whole, to look further in: every key in the same bucket of a string
+	// sort, or a numeric sort's guess at which bits vary corrected.
+	// Long runs of these point to keys with long common prefixes.
+	PrefixSkips int64
+	// QSortFallbacks is how many ranges were handed to quicksort for
+	// being shorter than the quicksort cutoff.
+	QSortFallbacks int64
+	// DepthFallbacks is how many ranges, at least as long as the cutoff,
+	// were quicksorted anyway: string ranges at MaxRadixDepth bytes in,
+	// or ranges at the recursion depth MaxMemoryBytes allows.
+	DepthFallbacks int64
+	// MaxDepth is the deepest level of radix recursion reached, counting
+	// the first pass as 0.
+	MaxDepth int64
+}
+
+// observe wraps sorter to count each task of a sort of data in s, given
+// the radix depth budget levels from Options.radixLevels.
+func (s *Stats) observe(sorter sortFunc, data sort.Interface, levels int) sortFunc {
+	strs := false
+	switch data.(type) {
+	case StringInterface, BytesInterface:
+		strs = true
+	}
+	return func(data sort.Interface, t task, sortRange func(task)) {
+		if t.offs < 0 {
+			// a quicksort partition, already counted as a fallback
+			sorter(data, t, sortRange)
+			return
+		}
+		switch {
+		case t.end-t.pos < t.cfg.qSortCutoff():
+			atomic.AddInt64(&s.QSortFallbacks, 1)
+		case strs && t.offs >= t.cfg.maxRadixDepth(), levels >= 0 && t.depth >= levels:
+			atomic.AddInt64(&s.DepthFallbacks, 1)
+		default:
+			atomic.AddInt64(&s.RadixPasses, 1)
+			for d := int64(t.depth); ; {
+				max := atomic.LoadInt64(&s.MaxDepth)
+				if d <= max || atomic.CompareAndSwapInt64(&s.MaxDepth, max, d) {
+					break
+				}
+			}
+			parent := t
+			sorter(data, t, func(t task) {
+				if t.pos == parent.pos && t.end == parent.end {
+					atomic.AddInt64(&s.PrefixSkips, 1)
+				}
+				sortRange(t)
+			})
+			return
+		}
+		sorter(data, t, sortRange)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// text is the element types of the slices of strings and byte slices
+// sorted directly below.  Comparisons convert to string, which for a
+// []byte doesn't allocate.
+type text interface {
+	~string | ~[]byte
+}
+
+// textSlice is a plain slice of strings or byte slices to be radix
+// sorted by value.
+// radixSortTexts reads the slice directly; the methods are for
+// parallelSort and heapSort.
+type textSlice[T text] []T
+
+func (p textSlice[T]) Len() int           { return len(p) }
+func (p textSlice[T]) Less(i, j int) bool { return string(p[i]) < string(p[j]) }
+func (p textSlice[T]) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// sortTexts radix sorts a in increasing order.
+func sortTexts[T text](a []T) {
+	l := len(a)
+	if l < qSortCutoff {
+		quickSortTexts(a, 0, 2*bitsLen(l))
+		return
+	}
+
+	parallelSort(textSlice[T](a), radixSortTexts[T], task{end: l})
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if string(a[i]) < string(a[i-1]) {
+			panic(panicMessage)
+		}
+	}
+}
+
+// SortStrings sorts a in increasing order.  It's ByString for a plain
+// []string, without the method calls: the radix passes read and swap the
+// slice's elements directly, and the quicksorts that finish each range
+// skip the bytes the radix passes already found every string in it to
+// share.  sortutil.Strings calls it.
+func SortStrings(a []string) { sortTexts(a) }
+
+// SortByteSlices is SortStrings for a [][]byte, sorting in bytes.Compare
+// order.  sortutil.Bytes calls it.
+func SortByteSlices(a [][]byte) { sortTexts(a) }
+
+// radixSortTexts is radixSortString for a textSlice, working on the
+// slice itself instead of calling Key and Swap.  Equal keys are equal
+// values, so ranges of them need no further sorting.
+func radixSortTexts[T text](dataI sort.Interface, t task, sortRange func(task)) {
+	offset, a := t.offs, dataI.(textSlice[T])[t.pos:t.end]
+	if len(a) < qSortCutoff || offset >= maxRadixDepth {
+		quickSortTexts(a, offset, 2*bitsLen(len(a)))
+		return
+	}
+
+	// swap too-short strings to start and count bucket sizes
+	bucketStarts, bucketEnds := [256]int{}, [256]int{}
+	short := 0
+	for i, k := range a {
+		if len(k) <= offset {
+			a[short], a[i] = k, a[short]
+			short++
+			continue
+		}
+		bucketStarts[k[offset]]++
+	}
+
+	pos := short
+	for i, c := range bucketStarts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+		if bucketStarts[i] == short && bucketEnds[i] == len(a) {
+			// everything was in the same bucket
+			sortRange(task{offset + 1, t.pos + short, t.end, t.depth + 1, t.cfg})
+			return
+		}
+	}
+
+	i := short
+	for curBucket, bucketEnd := range bucketEnds {
+		start := i
+		i = bucketStarts[curBucket]
+		for i < bucketEnd {
+			k := a[i]
+			destBucket := k[offset]
+			if destBucket == byte(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			j := bucketStarts[destBucket]
+			a[i], a[j] = a[j], k
+			bucketStarts[destBucket]++
+		}
+		if i > start+1 {
+			sortRange(task{offset + 1, t.pos + start, t.pos + i, t.depth + 1, t.cfg})
+		}
+	}
+}
+
+// quickSortTexts is quickSortInts for strings or byte slices all sharing
+// their first offset bytes, which comparisons skip.
+func quickSortTexts[T text](a []T, offset, maxDepth int) {
+	for len(a) > insertionCutoff {
+		if maxDepth == 0 {
+			heapSort(textSlice[T](a), 0, len(a))
+			return
+		}
+		maxDepth--
+
+		// move the median of the first, middle and last items to a[0]
+		m, hi := len(a)/2, len(a)-1
+		if string(a[m][offset:]) < string(a[0][offset:]) {
+			a[m], a[0] = a[0], a[m]
+		}
+		if string(a[hi][offset:]) < string(a[m][offset:]) {
+			a[hi], a[m] = a[m], a[hi]
+			if string(a[m][offset:]) < string(a[0][offset:]) {
+				a[m], a[0] = a[0], a[m]
+			}
+		}
+		a[0], a[m] = a[m], a[0]
+
+		pivot := a[0][offset:]
+		i, j := 1, hi
+		for {
+			for i <= j && string(a[i][offset:]) < string(pivot) {
+				i++
+			}
+			for i <= j && string(pivot) < string(a[j][offset:]) {
+				j--
+			}
+			if i >= j {
+				break
+			}
+			a[i], a[j] = a[j], a[i]
+			i++
+			j--
+		}
+		a[0], a[j] = a[j], a[0]
+
+		// recurse on the smaller side, loop on the larger
+		if j < len(a)-j {
+			quickSortTexts(a[:j], offset, maxDepth)
+			a = a[j+1:]
+		} else {
+			quickSortTexts(a[j+1:], offset, maxDepth)
+			a = a[:j]
+		}
+	}
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && string(a[j][offset:]) < string(a[j-1][offset:]); j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+// ByUint64TopK partially sorts data so data[0:k] holds the k items with
+// the smallest keys, in order, leaving data[k:] in no particular order.
+// Each radix pass partitions the range still in question and only
+// descends into the bucket straddling position k: buckets entirely before
+// k are sorted in full, and buckets after it are never looked at again.
+// For small k that's far less work than sorting everything.  If k >= Len,
+// it's the same as ByUint64; if k <= 0, data is left alone.  Where equal
+// keys straddle position k, any of them may end up on either side.
+func ByUint64TopK(data Uint64Interface, k int) {
+	l := data.Len()
+	if k <= 0 {
+		return
+	}
+	if k >= l {
+		ByUint64(data)
+		return
+	}
+
+	a, b := 0, l
+	for b-a >= qSortCutoff {
+		// find bits that vary in this range
+		min := data.Key(a)
+		max := min
+		for i := a; i < b; i++ {
+			key := data.Key(i)
+			if key < min {
+				min = key
+			}
+			if key > max {
+				max = key
+			}
+		}
+		diff := min ^ max
+		if diff == 0 {
+			break
+		}
+		log2diff := 0
+		for diff != 0 {
+			log2diff++
+			diff >>= 1
+		}
+		shift := uint(0)
+		if log2diff > radix {
+			shift = uint(log2diff - radix)
+		}
+
+		var counts [1 << radix]int
+		for i := a; i < b; i++ {
+			counts[(data.Key(i)>>shift)&mask]++
+		}
+		bucketEnds := partitionUint64(data, shift, a, &counts)
+
+		// sort buckets wholly before k, and go on with the one holding k
+		start := a
+		for _, end := range bucketEnds {
+			if end > k {
+				a, b = start, end
+				break
+			}
+			if end > start+1 {
+				if shift == 0 {
+					qSortEqualKeyRange(data, start, end)
+				} else {
+					nextShift := shift - radix
+					if shift < radix {
+						nextShift = 0
+					}
+					parallelSort(data, radixSortUint64, task{offs: int(nextShift), pos: start, end: end})
+				}
+			}
+			start = end
+		}
+	}
+	qSortTopK(data, a, k, b)
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < k; i++ {
+		if data.Less(i, i-1) {
+			panic(panicMessage)
+		}
+	}
+	for i := k; i < l; i++ {
+		if data.Less(i, k-1) {
+			panic(panicMessage)
+		}
+	}
+}
+
+// qSortTopK orders data[a:b] just enough that data[a:k] holds its
+// smallest items, in order.  It quicksorts pieces wholly before k, drops
+// pieces wholly after it, and finishes with a selection sort that stops
+// at k.
+func qSortTopK(data Uint64Interface, a, k, b int) {
+	for b-a > 12 {
+		mlo, mhi := doPivot(data, a, b)
+		if k <= mlo {
+			b = mlo
+			continue
+		}
+		qSort(data, a, mlo)
+		if k <= mhi {
+			// data[mlo:mhi] all equal the pivot
+			return
+		}
+		a = mhi
+	}
+	for i := a; i < k; i++ {
+		min := i
+		for j := i + 1; j < b; j++ {
+			if data.Less(j, min) {
+				min = j
+			}
+		}
+		data.Swap(i, min)
+	}
+}
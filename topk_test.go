@@ -0,0 +1,48 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByUint64TopK(t *testing.T) {
+	n := 10000
+	for _, k := range []int{-1, 0, 1, 100, 5000, n, n + 1} {
+		data := make([]uint64, n)
+		for i := range data {
+			data[i] = uint64(rand.Intn(n)) // some ties
+		}
+		orig := append([]uint64(nil), data...)
+		want := append([]uint64(nil), data...)
+		Uint64s(want)
+		varyQSortCutoff(func() {
+			copy(data, orig)
+			ByUint64TopK(Uint64Slice(data), k)
+			if k <= 0 {
+				for i := range data {
+					if data[i] != orig[i] {
+						t.Fatalf("k=%d moved data", k)
+					}
+				}
+				return
+			}
+			for i := 0; i < k && i < n; i++ {
+				if data[i] != want[i] {
+					t.Fatalf("k=%d: data[%d]=%d, want %d", k, i, data[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkByUint64TopK100(b *testing.B) {
+	benchApprox(b, func(a []uint64) { ByUint64TopK(Uint64Slice(a), 100) })
+}
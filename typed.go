@@ -0,0 +1,232 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "sort"
+
+// Integer is the element types Integers sorts: every integer type, as in
+// golang.org/x/exp/constraints.Integer.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// intSlice is a plain slice of integers to be radix sorted by its values.
+// Keys are the values with the sign bit flipped for signed types, and
+// are only as wide as the type, so a 32-bit type's keys vary in at most
+// the low 32 bits.  It's a Uint64Interface so guessIntShift and the
+// quicksort fallbacks can use it, but radixSortInts reads the slice
+// directly rather than through its methods.
+type intSlice[T Integer] struct {
+	a           []T
+	flip, width uint64
+}
+
+func newIntSlice[T Integer](a []T) intSlice[T] {
+	p := intSlice[T]{a: a, width: ^uint64(0)}
+	var one T = 1
+	bits := uint(8)
+	for bits < 64 && one<<bits != 0 {
+		bits *= 2
+	}
+	if bits < 64 {
+		p.width = 1<<bits - 1
+	}
+	if one<<(bits-1) < 0 {
+		p.flip = 1 << (bits - 1)
+	}
+	return p
+}
+
+func (p intSlice[T]) Len() int           { return len(p.a) }
+func (p intSlice[T]) Less(i, j int) bool { return p.a[i] < p.a[j] }
+func (p intSlice[T]) Swap(i, j int)      { p.a[i], p.a[j] = p.a[j], p.a[i] }
+func (p intSlice[T]) Key(i int) uint64   { return (uint64(p.a[i]) ^ p.flip) & p.width }
+
+// Integers sorts a in increasing order.  It's ByUint64 or ByInt64 for a
+// plain slice of integers, without the method calls: the radix passes
+// read and swap the slice's elements directly, and signed values are
+// keyed with their sign bit flipped so negatives sort first.  The
+// sortutil slice sorts, like sortutil.Uint64s and sortutil.Ints, call it.
+func Integers[T Integer](a []T) {
+	p := newIntSlice(a)
+	l := len(a)
+	if l < qSortCutoff {
+		quickSortInts(a, 2*bitsLen(l))
+		return
+	}
+
+	shift := guessIntShift(p, 0, l)
+	parallelSort(p, radixSortInts[T], task{offs: int(shift), end: l})
+
+	if !VerifyResults {
+		return
+	}
+	// check results!
+	for i := 1; i < l; i++ {
+		if a[i] < a[i-1] {
+			panic(panicMessage)
+		}
+	}
+}
+
+// radixSortInts is radixSortUint64 for an intSlice, working on the slice
+// itself instead of calling Key and Swap.  Equal keys are equal values,
+// so ranges of them need no further sorting.
+func radixSortInts[T Integer](dataI sort.Interface, t task, sortRange func(task)) {
+	p := dataI.(intSlice[T])
+	shift, a := uint(t.offs), p.a[t.pos:t.end]
+	if len(a) < qSortCutoff {
+		quickSortInts(a, 2*bitsLen(len(a)))
+		return
+	}
+	flip, width := p.flip, p.width
+
+	// use a single pass over the keys to bucket data and find min/max
+	// (for skipping over bits that are always identical)
+	var bucketStarts, bucketEnds [1 << radix]int
+	min := (uint64(a[0]) ^ flip) & width
+	max := min
+	for _, v := range a {
+		k := (uint64(v) ^ flip) & width
+		bucketStarts[(k>>shift)&mask]++
+		if k < min {
+			min = k
+		}
+		if k > max {
+			max = k
+		}
+	}
+
+	// skip past common prefixes, bail if all keys equal
+	diff := min ^ max
+	if diff == 0 {
+		return
+	}
+	if diff>>shift == 0 || diff>>(shift+radix) != 0 {
+		// find highest 1 bit in diff
+		log2diff := 0
+		for diff != 0 {
+			log2diff++
+			diff >>= 1
+		}
+		nextShift := log2diff - radix
+		if nextShift < 0 {
+			nextShift = 0
+		}
+		sortRange(task{nextShift, t.pos, t.end, t.depth + 1, t.cfg})
+		return
+	}
+
+	pos := 0
+	for i, c := range bucketStarts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+	}
+
+	for curBucket, bucketEnd := range bucketEnds {
+		i := bucketStarts[curBucket]
+		for i < bucketEnd {
+			v := a[i]
+			destBucket := (((uint64(v) ^ flip) & width) >> shift) & mask
+			if destBucket == uint64(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			j := bucketStarts[destBucket]
+			a[i], a[j] = a[j], v
+			bucketStarts[destBucket]++
+		}
+	}
+
+	if shift == 0 {
+		// each bucket is a unique value
+		return
+	}
+
+	nextShift := shift - radix
+	if shift < radix {
+		nextShift = 0
+	}
+	pos = 0
+	for _, end := range bucketEnds {
+		if end > pos+1 {
+			sortRange(task{int(nextShift), t.pos + pos, t.pos + end, t.depth + 1, t.cfg})
+		}
+		pos = end
+	}
+}
+
+// bitsLen is the number of bits needed to represent n.
+func bitsLen(n int) int {
+	b := 0
+	for ; n > 0; n >>= 1 {
+		b++
+	}
+	return b
+}
+
+// quickSortInts is quickSort for a plain slice of integers, for the
+// small ranges radixSortInts leaves: a median-of-three quicksort down to
+// insertionCutoff items, which are insertion sorted.  Past maxDepth
+// levels, it hands what's left to heapSort.
+func quickSortInts[T Integer](a []T, maxDepth int) {
+	for len(a) > insertionCutoff {
+		if maxDepth == 0 {
+			p := intSlice[T]{a: a}
+			heapSort(p, 0, len(a))
+			return
+		}
+		maxDepth--
+
+		// move the median of the first, middle and last items to a[0]
+		m, hi := len(a)/2, len(a)-1
+		if a[m] < a[0] {
+			a[m], a[0] = a[0], a[m]
+		}
+		if a[hi] < a[m] {
+			a[hi], a[m] = a[m], a[hi]
+			if a[m] < a[0] {
+				a[m], a[0] = a[0], a[m]
+			}
+		}
+		a[0], a[m] = a[m], a[0]
+
+		pivot := a[0]
+		i, j := 1, hi
+		for {
+			for i <= j && a[i] < pivot {
+				i++
+			}
+			for i <= j && pivot < a[j] {
+				j--
+			}
+			if i >= j {
+				break
+			}
+			a[i], a[j] = a[j], a[i]
+			i++
+			j--
+		}
+		a[0], a[j] = a[j], a[0]
+
+		// recurse on the smaller side, loop on the larger
+		if j < len(a)-j {
+			quickSortInts(a[:j], maxDepth)
+			a = a[j+1:]
+		} else {
+			quickSortInts(a[j+1:], maxDepth)
+			a = a[:j]
+		}
+	}
+	for i := 1; i < len(a); i++ {
+		for j := i; j > 0 && a[j] < a[j-1]; j-- {
+			a[j], a[j-1] = a[j-1], a[j]
+		}
+	}
+}
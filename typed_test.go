@@ -0,0 +1,207 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestIntegers(t *testing.T) {
+	for _, n := range []int{0, 1, 100, 10000} {
+		for _, bits := range []uint{1, 20, 64} {
+			data := make([]uint64, n)
+			for i := range data {
+				data[i] = rand.Uint64() >> (64 - bits)
+			}
+			varyQSortCutoff(func() {
+				rand.Shuffle(n, Uint64Slice(data).Swap)
+				Integers(data)
+				if !Uint64sAreSorted(data) {
+					t.Errorf("n=%d, bits=%d: uint64s didn't sort", n, bits)
+				}
+			})
+		}
+	}
+}
+
+func TestIntegersSigned(t *testing.T) {
+	const maxInt = int(^uint(0) >> 1)
+	for _, n := range []int{5, 100, 10000} {
+		data := make([]int, n)
+		for i := range data {
+			data[i] = rand.Int() - maxInt/2
+			if i%3 == 0 {
+				data[i] = rand.Intn(5) - 2
+			}
+		}
+		copy(data, []int{maxInt, 1, 0, -1, -maxInt - 1})
+		varyQSortCutoff(func() {
+			rand.Shuffle(n, IntSlice(data).Swap)
+			Integers(data)
+			if !IntsAreSorted(data) {
+				t.Errorf("n=%d: ints didn't sort", n)
+			}
+			if data[0] != -maxInt-1 || data[n-1] != maxInt {
+				t.Errorf("n=%d: extremes at %d and %d", n, data[0], data[n-1])
+			}
+		})
+	}
+}
+
+func TestIntegers32(t *testing.T) {
+	n := 10000
+	data := make([]int32, n)
+	for i := range data {
+		data[i] = int32(rand.Uint32())
+	}
+	copy(data, []int32{math.MaxInt32, 1, 0, -1, math.MinInt32})
+	varyQSortCutoff(func() {
+		rand.Shuffle(n, Int32Slice(data).Swap)
+		Integers(data)
+		if !Int32sAreSorted(data) {
+			t.Error("int32s didn't sort")
+		}
+	})
+
+	// keys of a 32-bit type are 32 bits wide, so a sort of values close
+	// to zero starts near the bottom of the key, not at bit 56
+	small := make([]int32, 1000)
+	for i := range small {
+		small[i] = int32(rand.Intn(2000) - 1000)
+	}
+	if shift := GuessInt32Shift(small); shift > 32 {
+		t.Errorf("int32s near zero: guessed shift %d", shift)
+	}
+}
+
+// sortNarrow sorts random values of a narrow integer type, with the
+// type's extremes included.
+func sortNarrow[T Integer](t *testing.T, min, max T) {
+	data := make([]T, 10000)
+	for i := range data {
+		data[i] = T(rand.Uint64())
+	}
+	copy(data, []T{max, 1, 0, min})
+	varyQSortCutoff(func() {
+		rand.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+		Integers(data)
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return data[i] < data[j] }) {
+			t.Errorf("%T didn't sort", data)
+		}
+		if data[0] != min || data[len(data)-1] != max {
+			t.Errorf("%T: extremes at %d and %d", data, data[0], data[len(data)-1])
+		}
+	})
+}
+
+func TestIntegersNarrow(t *testing.T) {
+	sortNarrow[int8](t, math.MinInt8, math.MaxInt8)
+	sortNarrow[int16](t, math.MinInt16, math.MaxInt16)
+	sortNarrow[uint8](t, 0, math.MaxUint8)
+	sortNarrow[uint16](t, 0, math.MaxUint16)
+	sortNarrow[uintptr](t, 0, ^uintptr(0))
+}
+
+func benchIntegers(b *testing.B, sorter func([]uint64)) {
+	b.StopTimer()
+	data := make([]uint64, 1<<20)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = rand.Uint64()
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkIntegersUint64(b *testing.B) { benchIntegers(b, Integers[uint64]) }
+func BenchmarkByUint64Slice(b *testing.B) {
+	benchIntegers(b, func(a []uint64) { ByUint64(Uint64Slice(a)) })
+}
+
+func TestSortStrings(t *testing.T) {
+	prefix := strings.Repeat("x", 40)
+	for _, n := range []int{0, 1, 100, 10000} {
+		for _, pre := range []string{"", prefix} {
+			data := randomWords(n)
+			for i := range data {
+				data[i] = pre + data[i]
+			}
+			varyQSortCutoff(func() {
+				rand.Shuffle(n, StringSlice(data).Swap)
+				SortStrings(data)
+				if !StringsAreSorted(data) {
+					t.Errorf("n=%d, prefix %q: didn't sort", n, pre)
+				}
+			})
+		}
+	}
+}
+
+func benchStrings(b *testing.B, sorter func([]string)) {
+	b.StopTimer()
+	data := make([]string, 1<<18)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = strconv.FormatUint(rand.Uint64(), 36)
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortStrings(b *testing.B) { benchStrings(b, SortStrings) }
+func BenchmarkByStringSlice(b *testing.B) {
+	benchStrings(b, func(a []string) { ByString(StringSlice(a)) })
+}
+
+func TestSortByteSlices(t *testing.T) {
+	prefix := strings.Repeat("x", 40)
+	for _, n := range []int{0, 1, 100, 10000} {
+		for _, pre := range []string{"", prefix} {
+			data := make([][]byte, n)
+			for i, w := range randomWords(n) {
+				data[i] = []byte(pre + w)
+			}
+			varyQSortCutoff(func() {
+				rand.Shuffle(n, BytesSlice(data).Swap)
+				SortByteSlices(data)
+				if !BytesAreSorted(data) {
+					t.Errorf("n=%d, prefix %q: didn't sort", n, pre)
+				}
+			})
+		}
+	}
+}
+
+func benchByteSlices(b *testing.B, sorter func([][]byte)) {
+	b.StopTimer()
+	b.ReportAllocs()
+	data := make([][]byte, 1<<18)
+	for i := 0; i < b.N; i++ {
+		for i := range data {
+			data[i] = strconv.AppendUint(data[i][:0], rand.Uint64(), 36)
+		}
+		b.StartTimer()
+		sorter(data)
+		b.StopTimer()
+	}
+}
+
+func BenchmarkSortByteSlices(b *testing.B) { benchByteSlices(b, SortByteSlices) }
+func BenchmarkByBytesSlice(b *testing.B) {
+	benchByteSlices(b, func(a [][]byte) { ByBytes(BytesSlice(a)) })
+}
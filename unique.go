@@ -0,0 +1,67 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "bytes"
+
+// UniqueUint64 compacts data, which must already be sorted by key (as
+// ByUint64 leaves it), so data[:n] holds one item for each distinct
+// key, in order, and returns n.  The items after n are the leftover
+// duplicates, in no particular order.  It compares keys with Key, never
+// calling Less, and only calls Swap to move an item into the compacted
+// prefix, so already-unique data isn't touched.
+func UniqueUint64(data Uint64Interface) int {
+	l := data.Len()
+	if l == 0 {
+		return 0
+	}
+	n := 1
+	for i := 1; i < l; i++ {
+		if data.Key(i) != data.Key(n-1) {
+			if i != n {
+				data.Swap(n, i)
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// UniqueString is UniqueUint64 for data sorted by ByString.
+func UniqueString(data StringInterface) int {
+	l := data.Len()
+	if l == 0 {
+		return 0
+	}
+	n := 1
+	for i := 1; i < l; i++ {
+		if data.Key(i) != data.Key(n-1) {
+			if i != n {
+				data.Swap(n, i)
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// UniqueBytes is UniqueUint64 for data sorted by ByBytes.
+func UniqueBytes(data BytesInterface) int {
+	l := data.Len()
+	if l == 0 {
+		return 0
+	}
+	n := 1
+	for i := 1; i < l; i++ {
+		if !bytes.Equal(data.Key(i), data.Key(n-1)) {
+			if i != n {
+				data.Swap(n, i)
+			}
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+// countingSwaps counts its calls to Swap.
+type countingSwaps struct {
+	Uint64Slice
+	swaps *int
+}
+
+func (p countingSwaps) Swap(i, j int) {
+	*p.swaps++
+	p.Uint64Slice.Swap(i, j)
+}
+
+func TestUnique(t *testing.T) {
+	for _, c := range []struct {
+		in, want  string
+		wantSwaps int
+	}{
+		{"[]", "[]", 0},
+		{"[5 5 5 5]", "[5]", 0},
+		{"[1 2 3 4]", "[1 2 3 4]", 0},
+		{"[1 1 2 3 3 3 4]", "[1 2 3 4]", 3},
+	} {
+		var u []uint64
+		var s []string
+		var b [][]byte
+		for _, f := range c.in[1 : len(c.in)-1] {
+			if f != ' ' {
+				u = append(u, uint64(f-'0'))
+				s = append(s, string(f))
+				b = append(b, []byte{byte(f)})
+			}
+		}
+
+		swaps := 0
+		n := UniqueUint64(countingSwaps{u, &swaps})
+		if got := fmt.Sprint(u[:n]); got != c.want {
+			t.Errorf("UniqueUint64(%s) left %s, want %s", c.in, got, c.want)
+		}
+		if swaps != c.wantSwaps {
+			t.Errorf("UniqueUint64(%s) made %d swaps, want %d", c.in, swaps, c.wantSwaps)
+		}
+		if n = UniqueString(StringSlice(s)); fmt.Sprint(s[:n]) != c.want {
+			t.Errorf("UniqueString(%s) left %s, want %s", c.in, s[:n], c.want)
+		}
+		n = UniqueBytes(BytesSlice(b))
+		got := make([]string, n)
+		for i := range got {
+			got[i] = string(b[i])
+		}
+		if fmt.Sprint(got) != c.want {
+			t.Errorf("UniqueBytes(%s) left %s, want %s", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import "unicode/utf8"
+
+// keyOrderedStrings orders a StringInterface by its keys alone.
+type keyOrderedStrings struct{ StringInterface }
+
+func (d keyOrderedStrings) Less(i, j int) bool { return d.Key(i) < d.Key(j) }
+
+// UTF8Less reports whether a sorts before b in the order ByStringUTF8
+// produces: valid UTF-8 before invalid, then by bytes.
+func UTF8Less(a, b string) bool {
+	va, vb := utf8.ValidString(a), utf8.ValidString(b)
+	if va != vb {
+		return va
+	}
+	return a < b
+}
+
+// ByStringUTF8 sorts data by string key, with every key that's valid
+// UTF-8 ahead of every key that isn't.  Valid keys are in code point
+// order, which for valid UTF-8 is the byte order ByString uses; invalid
+// keys follow, in byte order among themselves, instead of being
+// interleaved with the valid ones wherever their bytes happen to fall.
+//
+// Each key is validated once, in a first pass that swaps the invalid
+// ones to the end, the way the radix passes set aside keys too short to
+// have a byte at the current offset; the two groups are then radix sorted
+// separately.  The order comes from the keys alone (UTF8Less reproduces
+// it); data.Less is not called.
+func ByStringUTF8(data StringInterface) {
+	l := data.Len()
+	valid := l
+	for i := 0; i < valid; {
+		if utf8.ValidString(data.Key(i)) {
+			i++
+			continue
+		}
+		valid--
+		data.Swap(i, valid)
+	}
+
+	kd := keyOrderedStrings{data}
+	for _, side := range [2]task{{pos: 0, end: valid}, {pos: valid, end: l}} {
+		if side.end-side.pos < qSortCutoff {
+			qSort(kd, side.pos, side.end)
+			continue
+		}
+		parallelSort(kd, radixSortString, side)
+	}
+
+	if !VerifyResults {
+		return
+	}
+	// check results, within each group; the first pass split them
+	for i := 1; i < l; i++ {
+		if i != valid && kd.Less(i, i-1) {
+			panic(panicMessage)
+		}
+	}
+}
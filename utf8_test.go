@@ -0,0 +1,48 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestByStringUTF8(t *testing.T) {
+	words := []string{
+		"été", "\xe9t\xe9", "a", "日本", "\xe6\x97", "b\xff",
+		"", "é", "\xc3", "z",
+	}
+	ByStringUTF8(StringSlice(words))
+	got := fmt.Sprintf("%q", words)
+	want := fmt.Sprintf("%q", []string{
+		"", "a", "z", "é", "été", "日本",
+		"b\xff", "\xc3", "\xe6\x97", "\xe9t\xe9",
+	})
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// pieces of multibyte sequences, which glue into valid and invalid
+	// strings alike
+	pieces := []string{"a", "é", "日", "\xc3", "\xa9", "\xe6\x97", "\xff"}
+	varyQSortCutoff(func() {
+		data := make([]string, 10000)
+		for i := range data {
+			for j := rand.Intn(4); j > 0; j-- {
+				data[i] += pieces[rand.Intn(len(pieces))]
+			}
+		}
+		ByStringUTF8(StringSlice(data))
+		if !sort.SliceIsSorted(data, func(i, j int) bool { return UTF8Less(data[i], data[j]) }) {
+			t.Errorf("ByStringUTF8 didn't sort")
+		}
+	})
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts
+
+import (
+	"sort"
+	"sync"
+)
+
+const wideRadix = 16
+const wideMask = (1 << wideRadix) - 1
+
+// wideRadixMin is the smallest range radixSortUint64Wide buckets 16 bits
+// at a time; smaller ranges get the usual 8-bit passes.  With 65536
+// buckets, a pass over fewer items spends more time on its tables than on
+// the data.
+var wideRadixMin = 1 << 20
+
+// wideTables holds a 16-bit pass's bucketStarts and bucketEnds.  At 1MB
+// on 64-bit platforms they're too big for the stack, so they're pooled.
+type wideTables struct {
+	starts, ends [1 << wideRadix]int
+}
+
+var wideTablePool = sync.Pool{New: func() interface{} { return new(wideTables) }}
+
+// radixSortUint64Wide is radixSortUint64 with a 16-bit radix for ranges
+// of at least wideRadixMin items, halving the passes over big inputs.
+// Smaller ranges, including the buckets its passes produce, are handed to
+// radixSortUint64.  It takes Int64Interfaces wrapped in an intwrapper.
+func radixSortUint64Wide(dataI sort.Interface, t task, sortRange func(task)) {
+	if t.end-t.pos < wideRadixMin {
+		radixSortUint64(dataI, t, sortRange)
+		return
+	}
+	data := dataI.(Uint64Interface)
+	shift, a, b := uint(t.offs), t.pos, t.end
+
+	tables := wideTablePool.Get().(*wideTables)
+	defer wideTablePool.Put(tables)
+	bucketStarts, bucketEnds := tables.starts[:], tables.ends[:]
+	for i := range bucketStarts {
+		bucketStarts[i] = 0
+	}
+
+	// use a single pass over the keys to bucket data and find min/max
+	// (for skipping over bits that are always identical)
+	min := data.Key(a)
+	max := min
+	for i := a; i < b; i++ {
+		k := data.Key(i)
+		bucketStarts[(k>>shift)&wideMask]++
+		if k < min {
+			min = k
+		}
+		if k > max {
+			max = k
+		}
+	}
+
+	// skip past common prefixes, bail if all keys equal
+	diff := min ^ max
+	if diff == 0 {
+		qSortEqualKeyRange(data, a, b)
+		return
+	}
+	if diff>>shift == 0 || diff>>(shift+wideRadix) != 0 {
+		// find highest 1 bit in diff
+		log2diff := 0
+		for diff != 0 {
+			log2diff++
+			diff >>= 1
+		}
+		nextShift := log2diff - wideRadix
+		if nextShift < 0 {
+			nextShift = 0
+		}
+		sortRange(task{nextShift, a, b, t.depth + 1, t.cfg})
+		return
+	}
+
+	pos := a
+	for i, c := range bucketStarts {
+		bucketStarts[i] = pos
+		pos += c
+		bucketEnds[i] = pos
+	}
+
+	for curBucket, bucketEnd := range bucketEnds {
+		i := bucketStarts[curBucket]
+		for i < bucketEnd {
+			destBucket := (data.Key(i) >> shift) & wideMask
+			if destBucket == uint64(curBucket) {
+				i++
+				bucketStarts[destBucket]++
+				continue
+			}
+			data.Swap(i, bucketStarts[destBucket])
+			bucketStarts[destBucket]++
+		}
+	}
+
+	if shift == 0 {
+		// each bucket is a unique key
+		pos = a
+		for _, end := range bucketEnds {
+			if end > pos+1 {
+				qSortEqualKeyRange(data, pos, end)
+			}
+			pos = end
+		}
+		return
+	}
+
+	// buckets that will get 8-bit passes start at the top of the bits
+	// left, same as the wide ones
+	pos = a
+	for _, end := range bucketEnds {
+		if end > pos+1 {
+			bits := uint(wideRadix)
+			if end-pos < wideRadixMin {
+				bits = radix
+			}
+			nextShift := uint(0)
+			if shift > bits {
+				nextShift = shift - bits
+			}
+			sortRange(task{int(nextShift), pos, end, t.depth + 1, t.cfg})
+		}
+		pos = end
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2015 Randall Farmer. All rights reserved.
+
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/twotwotwo/sorts"
+	. "github.com/twotwotwo/sorts/sortutil"
+)
+
+func TestRadixBits(t *testing.T) {
+	defer SetWideRadixMin(SetWideRadixMin(1 << 10))
+	o := &Options{RadixBits: 16}
+	n := 50000
+	for _, bits := range []uint{1, 12, 20, 40, 63} {
+		u := make([]uint64, n)
+		s := make([]int64, n)
+		for i := range u {
+			u[i] = uint64(rand.Int63()) >> (63 - bits)
+			s[i] = int64(u[i]) - 1<<(bits-1)
+		}
+		u[0], s[0] = 1<<63, -1<<63
+		varyQSortCutoff(func() {
+			rand.Shuffle(n, Uint64Slice(u).Swap)
+			o.ByUint64(Uint64Slice(u))
+			if !Uint64sAreSorted(u) {
+				t.Errorf("bits=%d: uint64s didn't sort", bits)
+			}
+			rand.Shuffle(n, Int64Slice(s).Swap)
+			o.ByInt64(Int64Slice(s))
+			if !Int64sAreSorted(s) {
+				t.Errorf("bits=%d: int64s didn't sort", bits)
+			}
+		})
+	}
+}
+
+// BenchmarkRadixBits sorts random uint64s with 8- and 16-bit passes at a
+// few sizes, to show where (or whether) 16 bits starts to pay.  It lowers
+// the minimum range for 16-bit passes to 1<<16 so every size gets them.
+func BenchmarkRadixBits(b *testing.B) {
+	for _, n := range []int{1 << 16, 1 << 18, 1 << 20, 1 << 22} {
+		for _, bits := range []int{8, 16} {
+			b.Run(fmt.Sprintf("n=%d/bits=%d", n, bits), func(b *testing.B) {
+				defer SetWideRadixMin(SetWideRadixMin(1 << 16))
+				o := &Options{RadixBits: bits}
+				data := make([]uint64, n)
+				b.SetBytes(int64(8 * n))
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					for i := range data {
+						data[i] = uint64(rand.Int63())
+					}
+					b.StartTimer()
+					o.ByUint64(Uint64Slice(data))
+				}
+			})
+		}
+	}
+}